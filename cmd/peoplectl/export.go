@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"predictor/internal/domain/models"
+	"predictor/internal/lib/peopleio"
+	"predictor/internal/lib/query"
+	"time"
+)
+
+type PeopleExporter interface {
+	GetPeopleAfter(ctx context.Context, limit int64, filter models.PeopleFilter, sort []models.SortKey, cursor models.Cursor) ([]models.People, bool, error)
+}
+
+// runExport writes every person matching the filters, walking the table
+// page by page with a keyset cursor.
+func runExport(ctx context.Context, log *slog.Logger, exporter PeopleExporter, args []string) error {
+	const op = "peoplectl.export"
+
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+
+	path := fs.String("out", "", "file to write, required")
+	format := fs.String("format", "", "csv, ndjson or parquet, taken from the file extension by default")
+	filters := fs.String("filter", "", `filters as in GET /, e.g. "surname=Iv*&age_min=18"`)
+	sortBy := fs.String("sort", "", "sort keys as in GET /")
+	page := fs.Int64("page", 500, "records read per query")
+
+	_ = fs.Parse(args)
+
+	if *path == "" {
+		return fmt.Errorf("%s: -out is required", op)
+	}
+
+	if *page < 1 {
+		return fmt.Errorf("%s: -page must be positive", op)
+	}
+
+	values, err := url.ParseQuery(*filters)
+	if err != nil {
+		return fmt.Errorf("%s: -filter: %w", op, err)
+	}
+
+	filter, err := query.ParsePeopleFilter(values)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	sort, err := query.ParseSort(*sortBy)
+	if err == nil {
+		sort, err = query.ResolveSort(sort, filter)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	f, err := os.Create(*path)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer f.Close()
+
+	writer, err := peopleio.NewWriter(f, formatOf(*format, *path))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.String("op", op), slog.String("file", *path))
+
+	start := time.Now()
+	exported := 0
+
+	var cursor models.Cursor
+
+	for {
+		people, more, err := exporter.GetPeopleAfter(ctx, *page, filter, sort, cursor)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		for _, p := range people {
+			if err = writer.Write(p); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+		}
+
+		exported += len(people)
+
+		log.Info("export progress", slog.Int("exported", exported))
+
+		if !more {
+			break
+		}
+
+		cursor = query.NewCursor(people[len(people)-1], sort, false)
+	}
+
+	if err = writer.Flush(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = f.Close(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("export finished", slog.Int("exported", exported), slog.Duration("took", time.Since(start)))
+
+	return nil
+}
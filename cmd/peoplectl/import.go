@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"io"
+	"log/slog"
+	"os"
+	"predictor/internal/domain/models"
+	"predictor/internal/http-server/handlers/people/save"
+	"predictor/internal/lib/logger/sLogger"
+	"predictor/internal/lib/peopleio"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type PeopleImporter interface {
+	ImportPeople(ctx context.Context, people []models.People, enrich bool) ([]int64, error)
+}
+
+// runImport saves the records of a file in batches. After every batch the
+// number of records consumed is written to the state file, so an interrupted
+// import started again with the same state file picks up where it stopped.
+// A crash between a batch and its state update repeats that one batch.
+func runImport(ctx context.Context, log *slog.Logger, importer PeopleImporter, args []string) error {
+	const op = "peoplectl.import"
+
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+
+	path := fs.String("file", "", "file to import, required")
+	format := fs.String("format", "", "csv or ndjson, taken from the file extension by default")
+	enrich := fs.Bool("enrich", true, "queue people with missing attributes for enrichment")
+	batch := fs.Int("batch", 500, "records saved per statement")
+	statePath := fs.String("state", "", "progress file for resuming, <file>.progress by default")
+
+	_ = fs.Parse(args)
+
+	if *path == "" {
+		return fmt.Errorf("%s: -file is required", op)
+	}
+
+	if *batch < 1 || *batch > save.MaxBatch {
+		return fmt.Errorf("%s: -batch must be between 1 and %d", op, save.MaxBatch)
+	}
+
+	if *statePath == "" {
+		*statePath = *path + ".progress"
+	}
+
+	f, err := os.Open(*path)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer f.Close()
+
+	reader, err := peopleio.NewReader(f, formatOf(*format, *path))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	done, err := readState(*statePath)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.String("op", op), slog.String("file", *path))
+
+	if done > 0 {
+		log.Info("resuming import", slog.Int("skipped", done))
+	}
+
+	validate := validator.New()
+	start := time.Now()
+
+	var people []models.People
+	var read, saved, invalid int
+
+	flush := func() error {
+		if len(people) > 0 {
+			if _, err := importer.ImportPeople(ctx, people, *enrich); err != nil {
+				return err
+			}
+
+			saved += len(people)
+			people = people[:0]
+		}
+
+		if err := writeState(*statePath, read); err != nil {
+			return err
+		}
+
+		log.Info("import progress",
+			slog.Int("records", read),
+			slog.Int("saved", saved),
+			slog.Int("invalid", invalid),
+			slog.String("rate", fmt.Sprintf("%.0f/s", float64(saved)/time.Since(start).Seconds())),
+		)
+
+		return nil
+	}
+
+	for {
+		p, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil && !errors.Is(err, peopleio.ErrInvalidRecord) {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		read++
+
+		if read <= done {
+			continue
+		}
+
+		if err != nil {
+			log.Warn("skipping record", slog.Int("line", reader.Line()), sLogger.Error(err))
+			invalid++
+
+			continue
+		}
+
+		// Rows get the same checks as POST /people.
+		req := save.Request{Name: p.Name, Surname: p.Surname, Patronym: p.Patronymic}
+		if err = validate.Struct(req); err != nil {
+			log.Warn("skipping record", slog.Int("line", reader.Line()), sLogger.Error(err))
+			invalid++
+
+			continue
+		}
+
+		people = append(people, p)
+
+		if len(people) == *batch {
+			if err = flush(); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+		}
+	}
+
+	if err = flush(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = os.Remove(*statePath); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("import finished",
+		slog.Int("saved", saved),
+		slog.Int("invalid", invalid),
+		slog.Duration("took", time.Since(start)),
+	)
+
+	return nil
+}
+
+func readState(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	done, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, fmt.Errorf("corrupt state file %s", path)
+	}
+
+	return done, nil
+}
+
+// writeState replaces the state file atomically so a crash cannot leave it
+// half written.
+func writeState(path string, done int) error {
+	tmp := path + ".tmp"
+
+	if err := os.WriteFile(tmp, []byte(strconv.Itoa(done)+"\n"), 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"predictor/internal/config"
+	"predictor/internal/lib/logger/sLogger"
+	"predictor/internal/lib/peopleio"
+	"predictor/internal/storage/postgres"
+	"strings"
+	"syscall"
+)
+
+const usage = `usage: peoplectl <command> [flags]
+
+commands:
+  import   load people from a CSV or NDJSON file
+  export   write people matching filters to a CSV or NDJSON file
+
+Run "peoplectl <command> -h" for the flags of a command.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	command, args := os.Args[1], os.Args[2:]
+
+	if command != "import" && command != "export" {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cfg := config.MustLoad()
+
+	log := sLogger.SetupLogger(cfg.Env)
+
+	// Interrupting an import leaves its progress file behind for resuming.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	store, err := postgres.New(ctx, cfg.Storage)
+	if err != nil {
+		log.Error("failed to initialize storage", sLogger.Error(err))
+		os.Exit(1)
+	}
+	defer store.Close()
+
+	switch command {
+	case "import":
+		err = runImport(ctx, log, store, args)
+	case "export":
+		err = runExport(ctx, log, store, args)
+	}
+
+	if err != nil {
+		log.Error(command+" failed", sLogger.Error(err))
+		os.Exit(1)
+	}
+}
+
+// formatOf picks the format from the flag, or else from the file extension.
+func formatOf(format, path string) string {
+	if format != "" {
+		return format
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return peopleio.FormatCSV
+	case ".ndjson", ".jsonl":
+		return peopleio.FormatNDJSON
+	case ".parquet":
+		return peopleio.FormatParquet
+	default:
+		return ""
+	}
+}
@@ -1,20 +1,33 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"expvar"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	httpSwagger "github.com/swaggo/http-swagger"
 	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
 	_ "predictor/docs"
 	"predictor/internal/config"
 	"predictor/internal/http-server/handlers/people/delete"
+	"predictor/internal/http-server/handlers/people/enrich"
+	"predictor/internal/http-server/handlers/people/export"
 	"predictor/internal/http-server/handlers/people/get"
 	"predictor/internal/http-server/handlers/people/save"
 	"predictor/internal/http-server/handlers/people/update"
 	"predictor/internal/http-server/middleware/mwLogger"
+	"predictor/internal/lib/api"
+	"predictor/internal/lib/api/response"
 	"predictor/internal/lib/logger/sLogger"
-	"predictor/internal/storage/postgres"
+	"predictor/internal/worker/cachepurge"
+	"predictor/internal/worker/enrichment"
+	"sync"
+	"syscall"
+	"time"
 )
 
 // @title People API
@@ -27,17 +40,39 @@ func main() {
 
 	log := sLogger.SetupLogger(cfg.Env)
 
-	log.Info("starting predictor", slog.String("env", cfg.Env))
+	log.Info("starting predictor", slog.String("env", cfg.Env), slog.String("storage", cfg.StorageDriver))
 	log.Debug("debug messages are enabled")
 
-	store, err := postgres.New(cfg.Storage)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	store, err := newStorage(ctx, cfg)
 	if err != nil {
 		log.Error("failed to initialize storage", sLogger.Error(err))
 		return
 	}
+	defer store.Close()
 
 	log.Debug("storage is initialized")
 
+	enricher, err := api.New(cfg.Enrichment, store)
+	if err != nil {
+		log.Error("failed to initialize enricher", sLogger.Error(err))
+		return
+	}
+
+	log.Debug("enricher is initialized",
+		slog.String("age", cfg.Enrichment.AgeProvider),
+		slog.String("gender", cfg.Enrichment.GenderProvider),
+		slog.String("nationality", cfg.Enrichment.NationalityProvider),
+	)
+
+	pool, err := enrichment.New(log, enricher, store, cfg.Worker)
+	if err != nil {
+		log.Error("failed to initialize enrichment workers", sLogger.Error(err))
+		return
+	}
+
 	router := chi.NewRouter()
 
 	router.Use(middleware.RequestID)
@@ -45,11 +80,23 @@ func main() {
 	router.Use(middleware.Recoverer)
 	router.Use(middleware.URLFormat)
 
+	router.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		response.NotFound("route not found").Write(w, r)
+	})
+	router.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		response.NewProblem(http.StatusMethodNotAllowed, response.CodeInvalidRequest, "method not allowed").Write(w, r)
+	})
+
 	router.Post("/people", save.New(log, store))
+	router.Post("/people/batch", save.NewBatch(log, store))
 	router.Get("/", get.New(log, store))
+	router.Get("/people/export", export.New(log, store))
+	router.Get("/people/{id}", get.NewByID(log, store))
 	router.Delete("/people/{id}", delete.New(log, store))
 	router.Put("/people/{id}", update.New(log, store))
 	router.Patch("/people/{id}", update.New(log, store))
+	router.Post("/people/{id}/enrich", enrich.New(log, enricher, store))
+	router.Post("/people/enrich", enrich.NewBulk(log, store))
 	router.Get("/swagger/*", httpSwagger.WrapHandler)
 
 	log.Debug("router is initialized")
@@ -63,9 +110,74 @@ func main() {
 		IdleTimeout:  cfg.HTTPServer.IdleTimeout,
 	}
 
-	if err = srv.ListenAndServe(); err != nil {
-		log.Error("failed to start server")
+	purger := cachepurge.New(log, store, cfg.Enrichment.CachePurgeInterval)
+
+	workersDone := make(chan struct{})
+	go func() {
+		defer close(workersDone)
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			pool.Run(ctx)
+		}()
+		go func() {
+			defer wg.Done()
+			purger.Run(ctx)
+		}()
+
+		wg.Wait()
+	}()
+
+	// Runtime and cache metrics go on a separate listener, never on the API.
+	var admin *http.Server
+
+	if cfg.HTTPServer.AdminAddress != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/debug/vars", expvar.Handler())
+
+		admin = &http.Server{
+			Addr:              cfg.HTTPServer.AdminAddress,
+			Handler:           mux,
+			ReadHeaderTimeout: cfg.HTTPServer.Timeout,
+		}
+
+		log.Info("starting admin server", slog.String("address", cfg.HTTPServer.AdminAddress))
+
+		go func() {
+			if err := admin.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Error("failed to start admin server", sLogger.Error(err))
+			}
+		}()
+	}
+
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("failed to start server", sLogger.Error(err))
+			stop()
+		}
+	}()
+
+	<-ctx.Done()
+
+	log.Info("stopping server")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err = srv.Shutdown(shutdownCtx); err != nil {
+		log.Error("failed to stop server", sLogger.Error(err))
 	}
 
-	log.Error("server stopped")
+	if admin != nil {
+		if err = admin.Shutdown(shutdownCtx); err != nil {
+			log.Error("failed to stop admin server", sLogger.Error(err))
+		}
+	}
+
+	<-workersDone
+
+	log.Info("server stopped")
 }
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"predictor/internal/config"
+	"predictor/internal/http-server/handlers/people/delete"
+	"predictor/internal/http-server/handlers/people/enrich"
+	"predictor/internal/http-server/handlers/people/export"
+	"predictor/internal/http-server/handlers/people/get"
+	"predictor/internal/http-server/handlers/people/save"
+	"predictor/internal/http-server/handlers/people/update"
+	"predictor/internal/lib/api"
+	"predictor/internal/storage"
+	"predictor/internal/storage/memory"
+	"predictor/internal/storage/postgres"
+	"predictor/internal/worker/cachepurge"
+	"predictor/internal/worker/enrichment"
+)
+
+// Storage is everything the service needs from a storage driver.
+type Storage interface {
+	save.PeopleSaver
+	save.PeopleBatchSaver
+	get.PeopleGetter
+	get.PeopleByIDGetter
+	export.PeopleStreamer
+	delete.PeopleDeleter
+	update.PeopleUpdater
+	enrich.PeopleEnricher
+	enrich.PeopleEnqueuer
+	api.CacheStore
+	enrichment.JobStore
+	cachepurge.CachePurger
+	Close()
+}
+
+func newStorage(ctx context.Context, cfg *config.Config) (Storage, error) {
+	switch cfg.StorageDriver {
+	case storage.DriverPostgres:
+		store, err := postgres.New(ctx, cfg.Storage)
+		if err != nil {
+			return nil, err
+		}
+
+		return store, nil
+	case storage.DriverMemory:
+		return memory.New(), nil
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.StorageDriver)
+	}
+}
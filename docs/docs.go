@@ -30,49 +30,97 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Name",
+                        "description": "Fuzzy search over the full name; results get a Score and are sorted by it by default",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name, * matches any characters, ! in front negates",
                         "name": "name",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Surname",
+                        "description": "Surname, * matches any characters, ! in front negates",
                         "name": "surname",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Patronym",
+                        "description": "Patronym, * matches any characters, ! in front negates",
                         "name": "patronym",
                         "in": "query"
                     },
+                    {
+                        "type": "boolean",
+                        "description": "Match name, surname and patronym case-insensitively",
+                        "name": "ignore_case",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "description": "Age",
                         "name": "age",
                         "in": "query"
                     },
+                    {
+                        "type": "integer",
+                        "description": "Minimum age, inclusive",
+                        "name": "age_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum age, inclusive",
+                        "name": "age_max",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "Gender",
+                        "description": "Comma-separated genders, ! in front negates",
                         "name": "gender",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Nationality",
+                        "description": "Comma-separated nationalities, ! in front negates",
                         "name": "nationality",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "Keep people with an attribute from this source (predicted or manual)",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Keep people with an attribute predicted with at most this probability",
+                        "name": "max_probability",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated sort keys: id, name, surname, age, gender, nationality, created_at, score (with q), each optionally suffixed with :asc or :desc",
+                        "name": "sort",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "description": "Page number",
                         "name": "page",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "Opaque cursor from next_cursor or prev_cursor; pass it empty to start cursor pagination",
+                        "name": "cursor",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "Items per page",
+                        "description": "Items per page, at most 100",
                         "name": "limit",
                         "in": "query"
                     }
@@ -84,16 +132,16 @@ const docTemplate = `{
                             "$ref": "#/definitions/get.Response"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/get.Response"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/get.Response"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     }
                 }
@@ -101,7 +149,7 @@ const docTemplate = `{
         },
         "/people": {
             "post": {
-                "description": "Save person by name, surname and optional patronym",
+                "description": "Save person by name, surname and optional patronym. Age, gender and nationality are predicted in the background",
                 "consumes": [
                     "application/json"
                 ],
@@ -122,29 +170,394 @@ const docTemplate = `{
                         }
                     }
                 ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/save.Response"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "/people/{id}"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/people/batch": {
+            "post": {
+                "description": "Save up to 1000 people from a JSON array, or from NDJSON when sent as application/x-ndjson. Valid rows are saved together and invalid ones are reported per row; with atomic=true any invalid row rejects the whole batch",
+                "consumes": [
+                    "application/json",
+                    "application/x-ndjson"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "People"
+                ],
+                "parameters": [
+                    {
+                        "description": "People to save",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/save.Request"
+                            }
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Save nothing unless every row is valid",
+                        "name": "atomic",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/save.BatchResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/people/enrich": {
+            "post": {
+                "description": "Queue every person matching the filters for enrichment in the background",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "People"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Fuzzy search over the full name",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name, * matches any characters, ! in front negates",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Surname, * matches any characters, ! in front negates",
+                        "name": "surname",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Patronym, * matches any characters, ! in front negates",
+                        "name": "patronym",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Match name, surname and patronym case-insensitively",
+                        "name": "ignore_case",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Age",
+                        "name": "age",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum age, inclusive",
+                        "name": "age_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum age, inclusive",
+                        "name": "age_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated genders, ! in front negates",
+                        "name": "gender",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated nationalities, ! in front negates",
+                        "name": "nationality",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Keep people with an attribute from this source (predicted or manual)",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Keep people with an attribute predicted with at most this probability",
+                        "name": "max_probability",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Overwrite manually set attributes",
+                        "name": "overwrite_manual",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Re-enrich everyone, required when no filter is given",
+                        "name": "all",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/enrich.BulkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/people/export": {
+            "get": {
+                "description": "Stream every person matching the filters as CSV, NDJSON or Parquet. Takes the same filters and sort as the people list",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/vnd.apache.parquet"
+                ],
+                "tags": [
+                    "People"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "csv (default), ndjson or parquet",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fuzzy search over the full name",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name, * matches any characters, ! in front negates",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Surname, * matches any characters, ! in front negates",
+                        "name": "surname",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Patronym, * matches any characters, ! in front negates",
+                        "name": "patronym",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Match name, surname and patronym case-insensitively",
+                        "name": "ignore_case",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Age",
+                        "name": "age",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum age, inclusive",
+                        "name": "age_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum age, inclusive",
+                        "name": "age_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated genders, ! in front negates",
+                        "name": "gender",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated nationalities, ! in front negates",
+                        "name": "nationality",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Keep people with an attribute from this source (predicted or manual)",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Keep people with an attribute predicted with at most this probability",
+                        "name": "max_probability",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort keys as in the people list",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rows in the requested format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
                         }
                     }
                 }
             }
         },
         "/people/{id}": {
+            "get": {
+                "description": "Get person by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "People"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Person ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/get.ByIDResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            },
             "put": {
                 "description": "Update person by ID with any of their information (partial or full)",
                 "consumes": [
@@ -184,19 +597,19 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     }
                 }
@@ -228,16 +641,22 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     }
                 }
@@ -281,19 +700,80 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/response.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/people/{id}/enrich": {
+            "post": {
+                "description": "Predict age, gender and nationality of a person again and report what changed. Manually set attributes are kept unless overwrite_manual is true",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "People"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Person ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Overwrite manually set attributes",
+                        "name": "overwrite_manual",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/enrich.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
                         }
                     }
                 }
@@ -301,6 +781,48 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "enrich.BulkResponse": {
+            "type": "object",
+            "properties": {
+                "queued": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "enrich.Response": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AttributeChange"
+                    }
+                },
+                "skipped": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "get.ByIDResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.People"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
         "get.Response": {
             "type": "object",
             "properties": {
@@ -310,15 +832,18 @@ const docTemplate = `{
                         "$ref": "#/definitions/models.People"
                     }
                 },
-                "error": {
-                    "type": "string"
-                },
                 "limit": {
                     "type": "integer"
                 },
+                "next_cursor": {
+                    "type": "string"
+                },
                 "page": {
                     "type": "integer"
                 },
+                "prev_cursor": {
+                    "type": "string"
+                },
                 "status": {
                     "type": "string"
                 },
@@ -327,15 +852,38 @@ const docTemplate = `{
                 }
             }
         },
+        "models.AttributeChange": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "new": {
+                    "type": "string"
+                },
+                "old": {
+                    "type": "string"
+                }
+            }
+        },
         "models.People": {
             "type": "object",
             "properties": {
                 "age": {
                     "type": "integer"
                 },
+                "createdAt": {
+                    "type": "string"
+                },
+                "enrichmentStatus": {
+                    "type": "string"
+                },
                 "gender": {
                     "type": "string"
                 },
+                "id": {
+                    "type": "integer"
+                },
                 "name": {
                     "type": "string"
                 },
@@ -345,16 +893,103 @@ const docTemplate = `{
                 "patronymic": {
                     "type": "string"
                 },
+                "provenance": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.Provenance"
+                    }
+                },
+                "score": {
+                    "description": "Score is how well the person matches a search query, from 0 to 1.",
+                    "type": "number"
+                },
                 "surname": {
                     "type": "string"
                 }
             }
         },
+        "models.Provenance": {
+            "type": "object",
+            "properties": {
+                "probability": {
+                    "type": "number"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "fields": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.FieldError"
+                    }
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
         "response.Response": {
             "type": "object",
             "properties": {
-                "error": {
+                "status": {
                     "type": "string"
+                }
+            }
+        },
+        "save.BatchResponse": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "invalid": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/save.Result"
+                    }
                 },
                 "status": {
                     "type": "string"
@@ -379,6 +1014,37 @@ const docTemplate = `{
                 }
             }
         },
+        "save.Response": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.People"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "save.Result": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.FieldError"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
         "update.Request": {
             "type": "object",
             "required": [
@@ -1,33 +1,101 @@
 package config
 
 import (
+	"errors"
 	"github.com/ilyakaznacheev/cleanenv"
 	"github.com/joho/godotenv"
+	"io/fs"
 	"log"
 	"time"
 )
 
 type Config struct {
-	Env        string `env:"ENV" env-default:"local"`
-	Storage    Storage
-	HTTPServer HTTPServer
+	Env string `env:"ENV" env-default:"local"`
+	// StorageDriver is postgres, or memory to run without a database.
+	StorageDriver string `env:"STORAGE_DRIVER" env-default:"postgres"`
+	Storage       Storage
+	HTTPServer    HTTPServer
+	Enrichment    Enrichment
+	Worker        Worker
 }
 
+// Storage configures the postgres driver. Address, User and Name are required
+// by it, Password may be empty for trust or passfile authentication.
 type Storage struct {
-	Address  string `env:"DB_ADDRESS" env-required:"true"`
-	User     string `env:"DB_USER" env-required:"true"`
-	Name     string `env:"DB_NAME" env-required:"true"`
-	Password string `env:"DB_PASSWORD" env-required:"true"`
+	// Driver is stdlib for database/sql or pgxpool for the native pgx pool.
+	Driver   string `env:"DB_DRIVER" env-default:"stdlib"`
+	Address  string `env:"DB_ADDRESS"`
+	User     string `env:"DB_USER"`
+	Name     string `env:"DB_NAME"`
+	Password string `env:"DB_PASSWORD"`
+	// QueryTimeout bounds every storage call. Zero means no extra deadline.
+	QueryTimeout time.Duration `env:"DB_QUERY_TIMEOUT" env-default:"5s"`
+	// StatementTimeout is enforced by Postgres itself. Zero leaves the
+	// server setting.
+	StatementTimeout time.Duration `env:"DB_STATEMENT_TIMEOUT" env-default:"30s"`
+	SSLMode          string        `env:"DB_SSLMODE" env-default:"prefer"`
+	SSLRootCert      string        `env:"DB_SSLROOTCERT"`
+	ApplicationName  string        `env:"DB_APPLICATION_NAME" env-default:"predictor"`
+	MaxOpenConns     int           `env:"DB_MAX_OPEN_CONNS" env-default:"20"`
+	// MaxIdleConns only applies to the stdlib driver.
+	MaxIdleConns    int           `env:"DB_MAX_IDLE_CONNS" env-default:"5"`
+	ConnMaxLifetime time.Duration `env:"DB_CONN_MAX_LIFETIME" env-default:"30m"`
+	ConnMaxIdleTime time.Duration `env:"DB_CONN_MAX_IDLE_TIME" env-default:"5m"`
+	// ConnectRetries is how many more times startup tries to reach the
+	// database, waiting ConnectBackoff, then twice as long, and so on.
+	ConnectRetries int           `env:"DB_CONNECT_RETRIES" env-default:"5"`
+	ConnectBackoff time.Duration `env:"DB_CONNECT_BACKOFF" env-default:"1s"`
 }
 
 type HTTPServer struct {
 	Address     string        `env:"SERVER_ADDRESS" env-default:"localhost:8081"`
 	Timeout     time.Duration `env:"SERVER_TIMEOUT" env-default:"4s"`
 	IdleTimeout time.Duration `env:"SERVER_IDLE_TIMEOUT" env-default:"60s"`
+	// AdminAddress serves /debug/vars when set. Keep it off public networks.
+	AdminAddress string `env:"ADMIN_ADDRESS"`
+}
+
+type Enrichment struct {
+	AgeProvider         string        `env:"ENRICHMENT_AGE_PROVIDER" env-default:"agify"`
+	GenderProvider      string        `env:"ENRICHMENT_GENDER_PROVIDER" env-default:"genderize"`
+	NationalityProvider string        `env:"ENRICHMENT_NATIONALITY_PROVIDER" env-default:"nationalize"`
+	AgifyURL            string        `env:"AGIFY_URL" env-default:"https://api.agify.io"`
+	GenderizeURL        string        `env:"GENDERIZE_URL" env-default:"https://api.genderize.io"`
+	NationalizeURL      string        `env:"NATIONALIZE_URL" env-default:"https://api.nationalize.io"`
+	AgeTimeout          time.Duration `env:"ENRICHMENT_AGE_TIMEOUT" env-default:"3s"`
+	GenderTimeout       time.Duration `env:"ENRICHMENT_GENDER_TIMEOUT" env-default:"3s"`
+	NationalityTimeout  time.Duration `env:"ENRICHMENT_NATIONALITY_TIMEOUT" env-default:"3s"`
+	Retries             int           `env:"ENRICHMENT_RETRIES" env-default:"2"`
+	BackoffBase         time.Duration `env:"ENRICHMENT_BACKOFF_BASE" env-default:"100ms"`
+	BackoffMax          time.Duration `env:"ENRICHMENT_BACKOFF_MAX" env-default:"1s"`
+	BreakerThreshold    int           `env:"ENRICHMENT_BREAKER_THRESHOLD" env-default:"5"`
+	BreakerCooldown     time.Duration `env:"ENRICHMENT_BREAKER_COOLDOWN" env-default:"30s"`
+	Locale              string        `env:"ENRICHMENT_LOCALE"`
+	CacheTTL            time.Duration `env:"ENRICHMENT_CACHE_TTL" env-default:"168h"`
+	CacheSize           int           `env:"ENRICHMENT_CACHE_SIZE" env-default:"1000"`
+	// CachePurgeInterval is how often expired cache rows are deleted. Zero
+	// disables purging.
+	CachePurgeInterval time.Duration `env:"ENRICHMENT_CACHE_PURGE_INTERVAL" env-default:"1h"`
+	StaticAge          int           `env:"STATIC_AGE" env-default:"30"`
+	StaticGender       string        `env:"STATIC_GENDER" env-default:"male"`
+	StaticNationality  string        `env:"STATIC_NATIONALITY" env-default:"RU"`
+}
+
+type Worker struct {
+	Count        int           `env:"WORKER_COUNT" env-default:"4"`
+	PollInterval time.Duration `env:"WORKER_POLL_INTERVAL" env-default:"1s"`
+	Lease        time.Duration `env:"WORKER_LEASE" env-default:"1m"`
+	JobTimeout   time.Duration `env:"WORKER_JOB_TIMEOUT" env-default:"10s"`
+	MaxAttempts  int           `env:"WORKER_MAX_ATTEMPTS" env-default:"5"`
+	// RetryBackoff is the delay before the second attempt, doubling with
+	// every further one up to RetryBackoffMax.
+	RetryBackoff    time.Duration `env:"WORKER_RETRY_BACKOFF" env-default:"30s"`
+	RetryBackoffMax time.Duration `env:"WORKER_RETRY_BACKOFF_MAX" env-default:"1h"`
 }
 
 func MustLoad() *Config {
-	if err := godotenv.Load(); err != nil {
+	// The environment alone is enough, e.g. with the memory storage driver.
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
 		log.Fatal("Error loading .env file")
 	}
 
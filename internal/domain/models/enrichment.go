@@ -0,0 +1,38 @@
+package models
+
+const (
+	EnrichmentPending  = "pending"
+	EnrichmentComplete = "complete"
+	EnrichmentFailed   = "failed"
+)
+
+const (
+	SourcePredicted = "predicted"
+	SourceManual    = "manual"
+)
+
+const (
+	FieldAge         = "age"
+	FieldGender      = "gender"
+	FieldNationality = "nationality"
+)
+
+type EnrichmentJob struct {
+	ID              int64
+	PeopleID        int64
+	Name            string
+	Attempts        int
+	OverwriteManual bool
+}
+
+type AttributeChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// EnrichmentResult describes what applying a prediction did to a person.
+type EnrichmentResult struct {
+	Changes []AttributeChange `json:"changes"`
+	Skipped []string          `json:"skipped,omitempty"`
+}
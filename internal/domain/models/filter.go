@@ -0,0 +1,40 @@
+package models
+
+// PeopleFilter selects people. Zero values match everything.
+type PeopleFilter struct {
+	// Query is a fuzzy search over the full name, tolerant to typos.
+	Query          string
+	Name           TextFilter
+	Surname        TextFilter
+	Patronym       TextFilter
+	Gender         SetFilter
+	Nationality    SetFilter
+	Age            int
+	AgeMin         int
+	AgeMax         int
+	Source         string
+	MaxProbability float64
+}
+
+// IsZero reports whether the filter matches everything.
+func (f PeopleFilter) IsZero() bool {
+	return f.Query == "" &&
+		f.Name.Pattern == "" && f.Surname.Pattern == "" && f.Patronym.Pattern == "" &&
+		len(f.Gender.Values) == 0 && len(f.Nationality.Values) == 0 &&
+		f.Age == 0 && f.AgeMin == 0 && f.AgeMax == 0 &&
+		f.Source == "" && f.MaxProbability == 0
+}
+
+// TextFilter matches a text column. Pattern may contain * wildcards, e.g.
+// "Iv*" for a prefix or "*van*" for a substring.
+type TextFilter struct {
+	Pattern    string
+	IgnoreCase bool
+	Negate     bool
+}
+
+// SetFilter matches a column against any of Values.
+type SetFilter struct {
+	Values []string
+	Negate bool
+}
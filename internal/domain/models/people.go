@@ -1,10 +1,27 @@
 package models
 
+import "time"
+
 type People struct {
-	Name        string
-	Surname     string
-	Patronymic  string
-	Age         int
-	Gender      string
-	Nationality string
+	ID               int64
+	Name             string
+	Surname          string
+	Patronymic       string
+	Age              int
+	Gender           string
+	Nationality      string
+	EnrichmentStatus string
+	CreatedAt        time.Time
+	Provenance       map[string]Provenance
+	// Score is how well the person matches a search query, from 0 to 1.
+	Score *float64 `json:",omitempty"`
+}
+
+// Provenance tells where the current value of an attribute came from.
+// Probability is nil when the source gives none, e.g. for manual values.
+type Provenance struct {
+	Source      string
+	Provider    string
+	Probability *float64
+	UpdatedAt   time.Time
 }
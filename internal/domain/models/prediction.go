@@ -0,0 +1,49 @@
+package models
+
+type Prediction struct {
+	Age         AgePrediction
+	Gender      GenderPrediction
+	Nationality NationalityPrediction
+}
+
+type AgePrediction struct {
+	Provider string
+	Age      int
+	Count    int
+}
+
+type GenderPrediction struct {
+	Provider    string
+	Gender      string
+	Probability float64
+	Count       int
+}
+
+type NationalityPrediction struct {
+	Provider  string
+	Countries []CountryProbability
+	Count     int
+}
+
+type CountryProbability struct {
+	CountryID   string
+	Probability float64
+}
+
+// Top returns the most probable country, or an empty string when there are no candidates.
+func (n NationalityPrediction) Top() string {
+	return n.Best().CountryID
+}
+
+// Best returns the most probable candidate.
+func (n NationalityPrediction) Best() CountryProbability {
+	var best CountryProbability
+
+	for _, c := range n.Countries {
+		if best.CountryID == "" || c.Probability > best.Probability {
+			best = c
+		}
+	}
+
+	return best
+}
@@ -0,0 +1,37 @@
+package models
+
+const (
+	SortID          = "id"
+	SortName        = "name"
+	SortSurname     = "surname"
+	SortAge         = "age"
+	SortGender      = "gender"
+	SortNationality = "nationality"
+	SortCreatedAt   = "created_at"
+	SortScore       = "score"
+)
+
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// StableSort appends id to the sort keys unless it is already there, so every
+// row gets a unique position.
+func StableSort(sort []SortKey) []SortKey {
+	for _, k := range sort {
+		if k.Field == SortID {
+			return sort
+		}
+	}
+
+	return append(append([]SortKey(nil), sort...), SortKey{Field: SortID})
+}
+
+// Cursor points at a row of a keyset-paginated listing. Values holds the
+// row's value for every key of StableSort, in order. Backward asks for the
+// rows before that row instead of after it.
+type Cursor struct {
+	Values   []string
+	Backward bool
+}
@@ -1,6 +1,7 @@
 package delete
 
 import (
+	"context"
 	"errors"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -15,7 +16,7 @@ import (
 
 //go:generate go run github.com/vektra/mockery/v2 --name=PeopleDeleter
 type PeopleDeleter interface {
-	DeletePeople(id int64) error
+	DeletePeople(ctx context.Context, id int64) error
 }
 
 // New @Summary Delete person
@@ -25,8 +26,9 @@ type PeopleDeleter interface {
 // @Produce json
 // @Param id path int true "Person ID"
 // @Success 200 {object} response.Response
-// @Failure 404 {object} response.Response
-// @Failure 500 {object} response.Response
+// @Failure 400 {object} response.Problem
+// @Failure 404 {object} response.Problem
+// @Failure 500 {object} response.Problem
 // @Router /people/{id} [delete]
 func New(log *slog.Logger, peopleDeleter PeopleDeleter) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -43,25 +45,25 @@ func New(log *slog.Logger, peopleDeleter PeopleDeleter) http.HandlerFunc {
 		if err != nil {
 			log.Info("id is invalid")
 
-			render.JSON(w, r, response.Error("invalid request"))
+			response.InvalidRequest("id is invalid").Write(w, r)
 
 			return
 		}
 
 		log.Info("URL params read")
 
-		err = peopleDeleter.DeletePeople(id)
+		err = peopleDeleter.DeletePeople(r.Context(), id)
 		if errors.Is(err, storage.ErrPeopleNotFound) {
 			log.Info("people not found", "id", id)
 
-			render.JSON(w, r, response.Error("not found"))
+			response.NotFound("people not found").Write(w, r)
 
 			return
 		}
 		if err != nil {
 			log.Error("failed to delete people", sLogger.Error(err))
 
-			render.JSON(w, r, response.Error("internal server error"))
+			response.Internal().Write(w, r)
 
 			return
 		}
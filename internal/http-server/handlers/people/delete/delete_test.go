@@ -0,0 +1,70 @@
+package delete_test
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"predictor/internal/http-server/handlers/people/delete"
+	"predictor/internal/http-server/handlers/people/delete/mocks"
+	"predictor/internal/lib/api/response"
+	"predictor/internal/storage"
+	"strconv"
+	"testing"
+)
+
+func TestDeleteHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		mockErr    error
+		noCall     bool
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "deleted", id: "1", wantStatus: http.StatusOK},
+		{name: "not found", id: "2", mockErr: storage.ErrPeopleNotFound, wantStatus: http.StatusNotFound, wantCode: response.CodeNotFound},
+		{name: "wrapped not found", id: "3", mockErr: errors.Join(errors.New("storage"), storage.ErrPeopleNotFound), wantStatus: http.StatusNotFound, wantCode: response.CodeNotFound},
+		{name: "storage failure", id: "4", mockErr: errors.New("connection reset"), wantStatus: http.StatusInternalServerError, wantCode: response.CodeInternal},
+		{name: "invalid id", id: "abc", noCall: true, wantStatus: http.StatusBadRequest, wantCode: response.CodeInvalidRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deleter := mocks.NewPeopleDeleter(t)
+
+			if !tt.noCall {
+				id, err := strconv.ParseInt(tt.id, 10, 64)
+				require.NoError(t, err)
+
+				deleter.On("DeletePeople", mock.Anything, id).Return(tt.mockErr).Once()
+			}
+
+			router := chi.NewRouter()
+			router.Delete("/people/{id}", delete.New(slog.New(slog.DiscardHandler), deleter))
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/people/"+tt.id, nil))
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+
+			if tt.wantCode == "" {
+				return
+			}
+
+			assert.Equal(t, response.ContentTypeProblem, rec.Header().Get("Content-Type"))
+
+			var problem response.Problem
+
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+
+			assert.Equal(t, tt.wantStatus, problem.Status)
+			assert.Equal(t, tt.wantCode, problem.Code)
+		})
+	}
+}
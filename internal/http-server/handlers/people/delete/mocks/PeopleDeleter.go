@@ -2,24 +2,28 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
 
 // PeopleDeleter is an autogenerated mock type for the PeopleDeleter type
 type PeopleDeleter struct {
 	mock.Mock
 }
 
-// DeletePeople provides a mock function with given fields: id
-func (_m *PeopleDeleter) DeletePeople(id int64) error {
-	ret := _m.Called(id)
+// DeletePeople provides a mock function with given fields: ctx, id
+func (_m *PeopleDeleter) DeletePeople(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeletePeople")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int64) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Error(0)
 	}
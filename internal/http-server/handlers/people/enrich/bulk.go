@@ -0,0 +1,131 @@
+package enrich
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"predictor/internal/domain/models"
+	"predictor/internal/lib/api/response"
+	"predictor/internal/lib/logger/sLogger"
+	"predictor/internal/lib/query"
+	"strconv"
+)
+
+// boolParam parses an optional boolean query parameter, false when absent.
+func boolParam(q url.Values, name string) (bool, error) {
+	v := q.Get(name)
+	if v == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("%s must be a boolean", name)
+	}
+
+	return b, nil
+}
+
+type BulkResponse struct {
+	response.Response
+	Queued int64 `json:"queued"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2 --name=PeopleEnqueuer
+type PeopleEnqueuer interface {
+	EnqueueEnrichment(ctx context.Context, overwriteManual bool, filter models.PeopleFilter) (int64, error)
+}
+
+// NewBulk @Summary Re-enrich people
+// @Description Queue every person matching the filters for enrichment in the background
+// @Tags People
+// @Accept json
+// @Produce json
+// @Param q query string false "Fuzzy search over the full name"
+// @Param name query string false "Name, * matches any characters, ! in front negates"
+// @Param surname query string false "Surname, * matches any characters, ! in front negates"
+// @Param patronym query string false "Patronym, * matches any characters, ! in front negates"
+// @Param ignore_case query bool false "Match name, surname and patronym case-insensitively"
+// @Param age query int false "Age"
+// @Param age_min query int false "Minimum age, inclusive"
+// @Param age_max query int false "Maximum age, inclusive"
+// @Param gender query string false "Comma-separated genders, ! in front negates"
+// @Param nationality query string false "Comma-separated nationalities, ! in front negates"
+// @Param source query string false "Keep people with an attribute from this source (predicted or manual)"
+// @Param max_probability query number false "Keep people with an attribute predicted with at most this probability"
+// @Param overwrite_manual query bool false "Overwrite manually set attributes"
+// @Param all query bool false "Re-enrich everyone, required when no filter is given"
+// @Success 202 {object} BulkResponse
+// @Failure 400 {object} response.Problem
+// @Failure 500 {object} response.Problem
+// @Router /people/enrich [post]
+func NewBulk(log *slog.Logger, peopleEnqueuer PeopleEnqueuer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.people.enrich.NewBulk"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		q := r.URL.Query()
+
+		filter, err := query.ParsePeopleFilter(q)
+		if err != nil {
+			log.Info("filter is invalid", sLogger.Error(err))
+
+			response.InvalidRequest(err.Error()).Write(w, r)
+
+			return
+		}
+
+		all, err := boolParam(q, "all")
+		if err != nil {
+			log.Info("all is invalid", sLogger.Error(err))
+
+			response.InvalidRequest(err.Error()).Write(w, r)
+
+			return
+		}
+
+		// Re-enriching the whole table calls every provider for every person,
+		// so it has to be asked for explicitly.
+		if filter.IsZero() && !all {
+			log.Info("no filter given")
+
+			response.InvalidRequest("a filter or all=true is required").Write(w, r)
+
+			return
+		}
+
+		overwriteManual, err := boolParam(q, "overwrite_manual")
+		if err != nil {
+			log.Info("overwrite_manual is invalid", sLogger.Error(err))
+
+			response.InvalidRequest(err.Error()).Write(w, r)
+
+			return
+		}
+
+		queued, err := peopleEnqueuer.EnqueueEnrichment(r.Context(), overwriteManual, filter)
+		if err != nil {
+			log.Error("failed to queue enrichment", sLogger.Error(err))
+
+			response.Internal().Write(w, r)
+
+			return
+		}
+
+		log.Info("enrichment queued", slog.Int64("queued", queued))
+
+		render.Status(r, http.StatusAccepted)
+		render.JSON(w, r, BulkResponse{
+			Response: response.OK(),
+			Queued:   queued,
+		})
+	}
+}
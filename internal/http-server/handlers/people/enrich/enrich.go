@@ -0,0 +1,126 @@
+package enrich
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"predictor/internal/domain/models"
+	"predictor/internal/lib/api/response"
+	"predictor/internal/lib/logger/sLogger"
+	"predictor/internal/storage"
+	"strconv"
+)
+
+type Response struct {
+	response.Response
+	models.EnrichmentResult
+}
+
+//go:generate go run github.com/vektra/mockery/v2 --name=Enricher
+type Enricher interface {
+	Enrich(ctx context.Context, name string) (models.Prediction, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v2 --name=PeopleEnricher
+type PeopleEnricher interface {
+	GetPeopleByID(ctx context.Context, id int64) (models.People, error)
+	ApplyEnrichment(ctx context.Context, id int64, prediction models.Prediction, overwriteManual bool) (models.EnrichmentResult, error)
+}
+
+// New @Summary Re-enrich person
+// @Description Predict age, gender and nationality of a person again and report what changed. Manually set attributes are kept unless overwrite_manual is true
+// @Tags People
+// @Accept json
+// @Produce json
+// @Param id path int true "Person ID"
+// @Param overwrite_manual query bool false "Overwrite manually set attributes"
+// @Success 200 {object} Response
+// @Failure 400 {object} response.Problem
+// @Failure 404 {object} response.Problem
+// @Failure 502 {object} response.Problem
+// @Failure 500 {object} response.Problem
+// @Router /people/{id}/enrich [post]
+func New(log *slog.Logger, enricher Enricher, peopleEnricher PeopleEnricher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.people.enrich.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		strId := chi.URLParam(r, "id")
+
+		id, err := strconv.ParseInt(strId, 10, 64)
+		if err != nil {
+			log.Info("id is invalid")
+
+			response.InvalidRequest("id is invalid").Write(w, r)
+
+			return
+		}
+
+		overwriteManual, err := boolParam(r.URL.Query(), "overwrite_manual")
+		if err != nil {
+			log.Info("overwrite_manual is invalid", sLogger.Error(err))
+
+			response.InvalidRequest(err.Error()).Write(w, r)
+
+			return
+		}
+
+		log.Info("URL params read")
+
+		people, err := peopleEnricher.GetPeopleByID(r.Context(), id)
+		if errors.Is(err, storage.ErrPeopleNotFound) {
+			log.Info("people not found", "id", id)
+
+			response.NotFound("people not found").Write(w, r)
+
+			return
+		}
+		if err != nil {
+			log.Error("failed to get people", sLogger.Error(err))
+
+			response.Internal().Write(w, r)
+
+			return
+		}
+
+		prediction, err := enricher.Enrich(r.Context(), people.Name)
+		if err != nil {
+			log.Error("failed to enrich people", sLogger.Error(err))
+
+			response.UpstreamFailed("failed to predict attributes").Write(w, r)
+
+			return
+		}
+
+		result, err := peopleEnricher.ApplyEnrichment(r.Context(), id, prediction, overwriteManual)
+		if errors.Is(err, storage.ErrPeopleNotFound) {
+			log.Info("people not found", "id", id)
+
+			response.NotFound("people not found").Write(w, r)
+
+			return
+		}
+		if err != nil {
+			log.Error("failed to apply enrichment", sLogger.Error(err))
+
+			response.Internal().Write(w, r)
+
+			return
+		}
+
+		log.Info("people enriched", slog.Int("changes", len(result.Changes)))
+
+		render.JSON(w, r, Response{
+			Response:         response.OK(),
+			EnrichmentResult: result,
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package export
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5/middleware"
+	"io"
+	"log/slog"
+	"net/http"
+	"predictor/internal/domain/models"
+	"predictor/internal/lib/api/response"
+	"predictor/internal/lib/logger/sLogger"
+	"predictor/internal/lib/peopleio"
+	"predictor/internal/lib/query"
+	"time"
+)
+
+//go:generate go run github.com/vektra/mockery/v2 --name=PeopleStreamer
+type PeopleStreamer interface {
+	StreamPeople(ctx context.Context, filter models.PeopleFilter, sort []models.SortKey, fn func(models.People) error) error
+}
+
+// New @Summary Export people
+// @Description Stream every person matching the filters as CSV, NDJSON or Parquet. Takes the same filters and sort as the people list
+// @Tags People
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Produce application/vnd.apache.parquet
+// @Param format query string false "csv (default), ndjson or parquet"
+// @Param q query string false "Fuzzy search over the full name"
+// @Param name query string false "Name, * matches any characters, ! in front negates"
+// @Param surname query string false "Surname, * matches any characters, ! in front negates"
+// @Param patronym query string false "Patronym, * matches any characters, ! in front negates"
+// @Param ignore_case query bool false "Match name, surname and patronym case-insensitively"
+// @Param age query int false "Age"
+// @Param age_min query int false "Minimum age, inclusive"
+// @Param age_max query int false "Maximum age, inclusive"
+// @Param gender query string false "Comma-separated genders, ! in front negates"
+// @Param nationality query string false "Comma-separated nationalities, ! in front negates"
+// @Param source query string false "Keep people with an attribute from this source (predicted or manual)"
+// @Param max_probability query number false "Keep people with an attribute predicted with at most this probability"
+// @Param sort query string false "Sort keys as in the people list"
+// @Success 200 {string} string "Rows in the requested format"
+// @Failure 400 {object} response.Problem
+// @Failure 500 {object} response.Problem
+// @Router /people/export [get]
+func New(log *slog.Logger, peopleStreamer PeopleStreamer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.people.export.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		q := r.URL.Query()
+
+		format := q.Get("format")
+		if format == "" {
+			format = peopleio.FormatCSV
+		}
+
+		switch format {
+		case peopleio.FormatCSV, peopleio.FormatNDJSON, peopleio.FormatParquet:
+		default:
+			response.InvalidRequest("format must be csv, ndjson or parquet").Write(w, r)
+
+			return
+		}
+
+		filter, err := query.ParsePeopleFilter(q)
+		if err != nil {
+			log.Info("filter is invalid", sLogger.Error(err))
+
+			response.InvalidRequest(err.Error()).Write(w, r)
+
+			return
+		}
+
+		sort, err := query.ParseSort(q.Get("sort"))
+		if err == nil {
+			sort, err = query.ResolveSort(sort, filter)
+		}
+		if err != nil {
+			log.Info("sort is invalid", sLogger.Error(err))
+
+			response.InvalidRequest(err.Error()).Write(w, r)
+
+			return
+		}
+
+		out := &countingWriter{w: w}
+
+		writer, err := peopleio.NewWriter(out, format)
+		if err != nil {
+			log.Error("failed to create writer", sLogger.Error(err))
+
+			response.Internal().Write(w, r)
+
+			return
+		}
+
+		// An export may run longer than the server write timeout allows.
+		_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+		w.Header().Set("Content-Type", peopleio.ContentType(format))
+		w.Header().Set("Content-Disposition", `attachment; filename="people.`+format+`"`)
+
+		exported := 0
+
+		err = peopleStreamer.StreamPeople(r.Context(), filter, sort, func(p models.People) error {
+			exported++
+
+			return writer.Write(p)
+		})
+		if err != nil {
+			log.Error("failed to export people", sLogger.Error(err), slog.Int("exported", exported))
+
+			// Once bytes went out the status is sent, so the client only
+			// sees a cut-off body.
+			if out.n == 0 {
+				w.Header().Del("Content-Disposition")
+				response.Internal().Write(w, r)
+			}
+
+			return
+		}
+
+		if err = writer.Flush(); err != nil {
+			log.Error("failed to flush export", sLogger.Error(err))
+
+			return
+		}
+
+		log.Info("people exported", slog.Int("exported", exported))
+	}
+}
+
+// countingWriter tells whether anything reached the client yet.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+
+	return n, err
+}
@@ -0,0 +1,84 @@
+package get
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"predictor/internal/domain/models"
+	"predictor/internal/lib/api/response"
+	"predictor/internal/lib/logger/sLogger"
+	"predictor/internal/storage"
+	"strconv"
+)
+
+type ByIDResponse struct {
+	response.Response
+	Data models.People `json:"data"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2 --name=PeopleByIDGetter
+type PeopleByIDGetter interface {
+	GetPeopleByID(ctx context.Context, id int64) (models.People, error)
+}
+
+// NewByID @Summary Get person
+// @Description Get person by ID
+// @Tags People
+// @Accept json
+// @Produce json
+// @Param id path int true "Person ID"
+// @Success 200 {object} ByIDResponse
+// @Failure 400 {object} response.Problem
+// @Failure 404 {object} response.Problem
+// @Failure 500 {object} response.Problem
+// @Router /people/{id} [get]
+func NewByID(log *slog.Logger, peopleGetter PeopleByIDGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.people.get.NewByID"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		strId := chi.URLParam(r, "id")
+
+		id, err := strconv.ParseInt(strId, 10, 64)
+		if err != nil {
+			log.Info("id is invalid")
+
+			response.InvalidRequest("id is invalid").Write(w, r)
+
+			return
+		}
+
+		log.Info("URL params read")
+
+		data, err := peopleGetter.GetPeopleByID(r.Context(), id)
+		if errors.Is(err, storage.ErrPeopleNotFound) {
+			log.Info("people not found", "id", id)
+
+			response.NotFound("people not found").Write(w, r)
+
+			return
+		}
+		if err != nil {
+			log.Error("failed to get people", sLogger.Error(err))
+
+			response.Internal().Write(w, r)
+
+			return
+		}
+
+		log.Info("people got")
+
+		render.JSON(w, r, ByIDResponse{
+			Response: response.OK(),
+			Data:     data,
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package get
 
 import (
+	"context"
 	"errors"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
@@ -9,26 +10,33 @@ import (
 	"predictor/internal/domain/models"
 	"predictor/internal/lib/api/response"
 	"predictor/internal/lib/logger/sLogger"
+	"predictor/internal/lib/query"
 	"predictor/internal/storage"
 	"strconv"
 )
 
+// Response carries either a numbered page with the total count, or in cursor
+// mode the cursors of the neighbouring pages.
 type Response struct {
 	response.Response
-	Data  []models.People `json:"data,omitempty"`
-	Total int64           `json:"total,omitempty"`
-	Limit int64           `json:"limit"`
-	Page  int64           `json:"page"`
+	Data       []models.People `json:"data,omitempty"`
+	Total      int64           `json:"total,omitempty"`
+	Limit      int64           `json:"limit"`
+	Page       int64           `json:"page,omitempty"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+	PrevCursor string          `json:"prev_cursor,omitempty"`
 }
 
 const (
 	DefaultPage  = 1
 	DefaultLimit = 10
+	MaxLimit     = 100
 )
 
 //go:generate go run github.com/vektra/mockery/v2 --name=PeopleGetter
 type PeopleGetter interface {
-	GetPeople(limit, offset int64, name, surname, patronym, gender, nationality string, age int) ([]models.People, int64, error)
+	GetPeople(ctx context.Context, limit, offset int64, filter models.PeopleFilter, sort []models.SortKey) ([]models.People, int64, error)
+	GetPeopleAfter(ctx context.Context, limit int64, filter models.PeopleFilter, sort []models.SortKey, cursor models.Cursor) ([]models.People, bool, error)
 }
 
 func responseOK(w http.ResponseWriter, r *http.Request, data []models.People, total, limit, page int64) {
@@ -46,17 +54,25 @@ func responseOK(w http.ResponseWriter, r *http.Request, data []models.People, to
 // @Tags People
 // @Accept json
 // @Produce json
-// @Param name query string false "Name"
-// @Param surname query string false "Surname"
-// @Param patronym query string false "Patronym"
+// @Param q query string false "Fuzzy search over the full name; results get a Score and are sorted by it by default"
+// @Param name query string false "Name, * matches any characters, ! in front negates"
+// @Param surname query string false "Surname, * matches any characters, ! in front negates"
+// @Param patronym query string false "Patronym, * matches any characters, ! in front negates"
+// @Param ignore_case query bool false "Match name, surname and patronym case-insensitively"
 // @Param age query int false "Age"
-// @Param gender query string false "Gender"
-// @Param nationality query string false "Nationality"
+// @Param age_min query int false "Minimum age, inclusive"
+// @Param age_max query int false "Maximum age, inclusive"
+// @Param gender query string false "Comma-separated genders, ! in front negates"
+// @Param nationality query string false "Comma-separated nationalities, ! in front negates"
+// @Param source query string false "Keep people with an attribute from this source (predicted or manual)"
+// @Param max_probability query number false "Keep people with an attribute predicted with at most this probability"
+// @Param sort query string false "Comma-separated sort keys: id, name, surname, age, gender, nationality, created_at, score (with q), each optionally suffixed with :asc or :desc"
 // @Param page query int false "Page number"
-// @Param limit query int false "Items per page"
+// @Param cursor query string false "Opaque cursor from next_cursor or prev_cursor; pass it empty to start cursor pagination"
+// @Param limit query int false "Items per page, at most 100"
 // @Success 200 {object} Response
-// @Failure 404 {object} Response
-// @Failure 500 {object} Response
+// @Failure 400 {object} response.Problem
+// @Failure 500 {object} response.Problem
 // @Router / [get]
 func New(log *slog.Logger, peopleGetter PeopleGetter) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -69,16 +85,25 @@ func New(log *slog.Logger, peopleGetter PeopleGetter) http.HandlerFunc {
 
 		q := r.URL.Query()
 
-		name := q.Get("name")
-		surname := q.Get("surname")
-		patronym := q.Get("patronym")
-		gender := q.Get("gender")
-		nationality := q.Get("nationality")
-		age, _ := strconv.Atoi(q.Get("age"))
+		filter, err := query.ParsePeopleFilter(q)
+		if err != nil {
+			log.Info("filter is invalid", sLogger.Error(err))
 
-		page, err := strconv.ParseInt(q.Get("page"), 10, 64)
-		if err != nil || page < 1 {
-			page = DefaultPage
+			response.InvalidRequest(err.Error()).Write(w, r)
+
+			return
+		}
+
+		sort, err := query.ParseSort(q.Get("sort"))
+		if err == nil {
+			sort, err = query.ResolveSort(sort, filter)
+		}
+		if err != nil {
+			log.Info("sort is invalid", sLogger.Error(err))
+
+			response.InvalidRequest(err.Error()).Write(w, r)
+
+			return
 		}
 
 		limit, err := strconv.ParseInt(q.Get("limit"), 10, 64)
@@ -86,14 +111,67 @@ func New(log *slog.Logger, peopleGetter PeopleGetter) http.HandlerFunc {
 			limit = DefaultLimit
 		}
 
+		if limit > MaxLimit {
+			limit = MaxLimit
+		}
+
+		if q.Has("cursor") {
+			cursor, err := query.DecodeCursor(q.Get("cursor"), sort)
+			if q.Get("cursor") != "" && err != nil {
+				log.Info("cursor is invalid", sLogger.Error(err))
+
+				response.InvalidRequest(err.Error()).Write(w, r)
+
+				return
+			}
+
+			data, more, err := peopleGetter.GetPeopleAfter(r.Context(), limit, filter, sort, cursor)
+			if err != nil {
+				log.Error("failed to get people", sLogger.Error(err))
+
+				response.Internal().Write(w, r)
+
+				return
+			}
+
+			log.Info("people got")
+
+			resp := Response{
+				Response: response.OK(),
+				Data:     data,
+				Limit:    limit,
+			}
+
+			if len(data) > 0 {
+				// Moving on is possible wherever rows remain, and back
+				// wherever we came from.
+				if more || cursor.Backward {
+					resp.NextCursor = query.EncodeCursor(data[len(data)-1], sort, false)
+				}
+
+				if (more && cursor.Backward) || (len(cursor.Values) > 0 && !cursor.Backward) {
+					resp.PrevCursor = query.EncodeCursor(data[0], sort, true)
+				}
+			}
+
+			render.JSON(w, r, resp)
+
+			return
+		}
+
+		page, err := strconv.ParseInt(q.Get("page"), 10, 64)
+		if err != nil || page < 1 {
+			page = DefaultPage
+		}
+
 		offset := (page - 1) * limit
 
-		data, total, err := peopleGetter.GetPeople(limit, offset, name, surname, patronym, gender, nationality, age)
+		data, total, err := peopleGetter.GetPeople(r.Context(), limit, offset, filter, sort)
 		if err != nil {
 			if !errors.Is(err, storage.ErrPeopleNotFound) {
 				log.Error("failed to get people", sLogger.Error(err))
 
-				render.JSON(w, r, response.Error("internal server error"))
+				response.Internal().Write(w, r)
 
 				return
 			}
@@ -3,9 +3,11 @@
 package mocks
 
 import (
-	models "predictor/internal/domain/models"
+	context "context"
 
 	mock "github.com/stretchr/testify/mock"
+
+	models "predictor/internal/domain/models"
 )
 
 // PeopleGetter is an autogenerated mock type for the PeopleGetter type
@@ -13,9 +15,9 @@ type PeopleGetter struct {
 	mock.Mock
 }
 
-// GetPeople provides a mock function with given fields: limit, offset, name, surname, patronym, gender, nationality, age
-func (_m *PeopleGetter) GetPeople(limit int64, offset int64, name string, surname string, patronym string, gender string, nationality string, age int) ([]models.People, int64, error) {
-	ret := _m.Called(limit, offset, name, surname, patronym, gender, nationality, age)
+// GetPeople provides a mock function with given fields: ctx, limit, offset, filter, sort
+func (_m *PeopleGetter) GetPeople(ctx context.Context, limit int64, offset int64, filter models.PeopleFilter, sort []models.SortKey) ([]models.People, int64, error) {
+	ret := _m.Called(ctx, limit, offset, filter, sort)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetPeople")
@@ -24,25 +26,62 @@ func (_m *PeopleGetter) GetPeople(limit int64, offset int64, name string, surnam
 	var r0 []models.People
 	var r1 int64
 	var r2 error
-	if rf, ok := ret.Get(0).(func(int64, int64, string, string, string, string, string, int) ([]models.People, int64, error)); ok {
-		return rf(limit, offset, name, surname, patronym, gender, nationality, age)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, models.PeopleFilter, []models.SortKey) ([]models.People, int64, error)); ok {
+		return rf(ctx, limit, offset, filter, sort)
 	}
-	if rf, ok := ret.Get(0).(func(int64, int64, string, string, string, string, string, int) []models.People); ok {
-		r0 = rf(limit, offset, name, surname, patronym, gender, nationality, age)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, models.PeopleFilter, []models.SortKey) []models.People); ok {
+		r0 = rf(ctx, limit, offset, filter, sort)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]models.People)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64, int64, string, string, string, string, string, int) int64); ok {
-		r1 = rf(limit, offset, name, surname, patronym, gender, nationality, age)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, models.PeopleFilter, []models.SortKey) int64); ok {
+		r1 = rf(ctx, limit, offset, filter, sort)
 	} else {
 		r1 = ret.Get(1).(int64)
 	}
 
-	if rf, ok := ret.Get(2).(func(int64, int64, string, string, string, string, string, int) error); ok {
-		r2 = rf(limit, offset, name, surname, patronym, gender, nationality, age)
+	if rf, ok := ret.Get(2).(func(context.Context, int64, int64, models.PeopleFilter, []models.SortKey) error); ok {
+		r2 = rf(ctx, limit, offset, filter, sort)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetPeopleAfter provides a mock function with given fields: ctx, limit, filter, sort, cursor
+func (_m *PeopleGetter) GetPeopleAfter(ctx context.Context, limit int64, filter models.PeopleFilter, sort []models.SortKey, cursor models.Cursor) ([]models.People, bool, error) {
+	ret := _m.Called(ctx, limit, filter, sort, cursor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPeopleAfter")
+	}
+
+	var r0 []models.People
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, models.PeopleFilter, []models.SortKey, models.Cursor) ([]models.People, bool, error)); ok {
+		return rf(ctx, limit, filter, sort, cursor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, models.PeopleFilter, []models.SortKey, models.Cursor) []models.People); ok {
+		r0 = rf(ctx, limit, filter, sort, cursor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.People)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, models.PeopleFilter, []models.SortKey, models.Cursor) bool); ok {
+		r1 = rf(ctx, limit, filter, sort, cursor)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int64, models.PeopleFilter, []models.SortKey, models.Cursor) error); ok {
+		r2 = rf(ctx, limit, filter, sort, cursor)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -0,0 +1,268 @@
+package save
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"mime"
+	"net/http"
+	"predictor/internal/domain/models"
+	"predictor/internal/lib/api/response"
+	"predictor/internal/lib/logger/sLogger"
+	"strconv"
+	"strings"
+)
+
+// MaxBatch is the largest number of people accepted by one batch request.
+const MaxBatch = 1000
+
+// maxRowBytes bounds one row of a batch, and with MaxBatch the whole body.
+const maxRowBytes = 64 * 1024
+
+const ContentTypeNDJSON = "application/x-ndjson"
+
+const (
+	StatusCreated = "created"
+	StatusInvalid = "invalid"
+)
+
+// Result reports what happened to one row of the batch, by its position.
+type Result struct {
+	Index  int                   `json:"index"`
+	Status string                `json:"status"`
+	ID     int64                 `json:"id,omitempty"`
+	Errors []response.FieldError `json:"errors,omitempty"`
+}
+
+type BatchResponse struct {
+	response.Response
+	Created int      `json:"created"`
+	Invalid int      `json:"invalid"`
+	Results []Result `json:"results"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2 --name=PeopleBatchSaver
+type PeopleBatchSaver interface {
+	SavePeopleBatch(ctx context.Context, people []models.People) ([]int64, error)
+}
+
+// NewBatch @Summary Save people in batch
+// @Description Save up to 1000 people from a JSON array, or from NDJSON when sent as application/x-ndjson. Valid rows are saved together and invalid ones are reported per row; with atomic=true any invalid row rejects the whole batch
+// @Tags People
+// @Accept json
+// @Accept application/x-ndjson
+// @Produce json
+// @Param req body []Request true "People to save"
+// @Param atomic query bool false "Save nothing unless every row is valid"
+// @Success 200 {object} BatchResponse
+// @Failure 400 {object} response.Problem
+// @Failure 413 {object} response.Problem
+// @Failure 422 {object} response.Problem
+// @Failure 500 {object} response.Problem
+// @Router /people/batch [post]
+func NewBatch(log *slog.Logger, batchSaver PeopleBatchSaver) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.people.save.NewBatch"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		atomic := false
+		if v := r.URL.Query().Get("atomic"); v != "" {
+			var err error
+			if atomic, err = strconv.ParseBool(v); err != nil {
+				response.InvalidRequest("atomic must be a boolean").Write(w, r)
+
+				return
+			}
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, MaxBatch*maxRowBytes)
+
+		raw, err := readBatch(r)
+
+		var bodyErr *http.MaxBytesError
+		if errors.As(err, &bodyErr) {
+			err = fmt.Errorf("batch body exceeds %d bytes", bodyErr.Limit)
+		}
+		if errors.Is(err, errBatchTooLarge) || bodyErr != nil {
+			log.Info("batch is too large")
+
+			response.NewProblem(http.StatusRequestEntityTooLarge, response.CodeInvalidRequest, err.Error()).Write(w, r)
+
+			return
+		}
+		if err != nil {
+			log.Error("failed to decode request", sLogger.Error(err))
+
+			response.InvalidRequest("failed to decode request").Write(w, r)
+
+			return
+		}
+
+		if len(raw) == 0 {
+			response.InvalidRequest("batch is empty").Write(w, r)
+
+			return
+		}
+
+		results := make([]Result, len(raw))
+		people := make([]models.People, 0, len(raw))
+		rows := make([]int, 0, len(raw))
+
+		validate := validator.New()
+
+		for i, item := range raw {
+			results[i] = Result{Index: i}
+
+			var req Request
+
+			if err = json.Unmarshal(item, &req); err != nil {
+				results[i].Status = StatusInvalid
+				results[i].Errors = []response.FieldError{{Message: "failed to decode row"}}
+
+				continue
+			}
+
+			if err = validate.Struct(req); err != nil {
+				var validateErr validator.ValidationErrors
+
+				errors.As(err, &validateErr)
+
+				results[i].Status = StatusInvalid
+				results[i].Errors = response.ValidationError(validateErr).Fields
+
+				continue
+			}
+
+			people = append(people, models.People{
+				Name:       req.Name,
+				Surname:    req.Surname,
+				Patronymic: req.Patronym,
+			})
+			rows = append(rows, i)
+		}
+
+		invalid := len(raw) - len(people)
+
+		if atomic && invalid > 0 {
+			log.Info("batch has invalid rows", slog.Int("invalid", invalid))
+
+			p := response.NewProblem(http.StatusUnprocessableEntity, response.CodeValidationFailed, "batch has invalid rows")
+			for _, res := range results {
+				for _, fe := range res.Errors {
+					fe.Field = fmt.Sprintf("[%d]", res.Index) + strings.TrimSuffix("."+fe.Field, ".")
+					p.Fields = append(p.Fields, fe)
+				}
+			}
+
+			p.Write(w, r)
+
+			return
+		}
+
+		if len(people) > 0 {
+			ids, err := batchSaver.SavePeopleBatch(r.Context(), people)
+			if err != nil {
+				log.Error("failed to save people", sLogger.Error(err))
+
+				response.Internal().Write(w, r)
+
+				return
+			}
+
+			for i, id := range ids {
+				results[rows[i]].Status = StatusCreated
+				results[rows[i]].ID = id
+			}
+		}
+
+		log.Info("people saved", slog.Int("created", len(people)), slog.Int("invalid", invalid))
+
+		render.JSON(w, r, BatchResponse{
+			Response: response.OK(),
+			Created:  len(people),
+			Invalid:  invalid,
+			Results:  results,
+		})
+	}
+}
+
+var errBatchTooLarge = fmt.Errorf("batch holds more than %d people", MaxBatch)
+
+// readBatch splits the body into rows without decoding them, so a bad row
+// does not spoil the others. Reading stops at the first row past MaxBatch.
+func readBatch(r *http.Request) ([]json.RawMessage, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	if mediaType != ContentTypeNDJSON {
+		return readArray(r)
+	}
+
+	var raw []json.RawMessage
+
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(make([]byte, 0, 4*1024), maxRowBytes)
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		if len(raw) == MaxBatch {
+			return nil, errBatchTooLarge
+		}
+
+		raw = append(raw, json.RawMessage(bytes.Clone(line)))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return raw, nil
+}
+
+func readArray(r *http.Request) ([]json.RawMessage, error) {
+	dec := json.NewDecoder(r.Body)
+
+	t, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if t != json.Delim('[') {
+		return nil, errors.New("batch must be a JSON array")
+	}
+
+	var raw []json.RawMessage
+
+	for dec.More() {
+		if len(raw) == MaxBatch {
+			return nil, errBatchTooLarge
+		}
+
+		var item json.RawMessage
+
+		if err = dec.Decode(&item); err != nil {
+			return nil, err
+		}
+
+		raw = append(raw, item)
+	}
+
+	if _, err = dec.Token(); err != nil {
+		return nil, err
+	}
+
+	return raw, nil
+}
@@ -2,34 +2,39 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	context "context"
+	models "predictor/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
 
 // PeopleSaver is an autogenerated mock type for the PeopleSaver type
 type PeopleSaver struct {
 	mock.Mock
 }
 
-// SavePeople provides a mock function with given fields: name, surname, patronym, gender, nationality, age
-func (_m *PeopleSaver) SavePeople(name string, surname string, patronym string, gender string, nationality string, age int) (int64, error) {
-	ret := _m.Called(name, surname, patronym, gender, nationality, age)
+// SavePeople provides a mock function with given fields: ctx, name, surname, patronym
+func (_m *PeopleSaver) SavePeople(ctx context.Context, name string, surname string, patronym string) (models.People, error) {
+	ret := _m.Called(ctx, name, surname, patronym)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SavePeople")
 	}
 
-	var r0 int64
+	var r0 models.People
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string, string, string, string, int) (int64, error)); ok {
-		return rf(name, surname, patronym, gender, nationality, age)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (models.People, error)); ok {
+		return rf(ctx, name, surname, patronym)
 	}
-	if rf, ok := ret.Get(0).(func(string, string, string, string, string, int) int64); ok {
-		r0 = rf(name, surname, patronym, gender, nationality, age)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) models.People); ok {
+		r0 = rf(ctx, name, surname, patronym)
 	} else {
-		r0 = ret.Get(0).(int64)
+		r0 = ret.Get(0).(models.People)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string, string, string, string, int) error); ok {
-		r1 = rf(name, surname, patronym, gender, nationality, age)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, name, surname, patronym)
 	} else {
 		r1 = ret.Error(1)
 	}
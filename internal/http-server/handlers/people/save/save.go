@@ -1,15 +1,17 @@
 package save
 
 import (
+	"context"
 	"errors"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
 	"github.com/go-playground/validator/v10"
 	"log/slog"
 	"net/http"
-	"predictor/internal/lib/api"
+	"predictor/internal/domain/models"
 	"predictor/internal/lib/api/response"
 	"predictor/internal/lib/logger/sLogger"
+	"strconv"
 )
 
 type Request struct {
@@ -18,20 +20,27 @@ type Request struct {
 	Patronym string `json:"patronym,omitempty"`
 }
 
+type Response struct {
+	response.Response
+	Data models.People `json:"data"`
+}
+
 //go:generate go run github.com/vektra/mockery/v2 --name=PeopleSaver
 type PeopleSaver interface {
-	SavePeople(name, surname, patronym, gender, nationality string, age int) (int64, error)
+	SavePeople(ctx context.Context, name, surname, patronym string) (models.People, error)
 }
 
 // New @Summary Save person
-// @Description Save person by name, surname and optional patronym
+// @Description Save person by name, surname and optional patronym. Age, gender and nationality are predicted in the background
 // @Tags People
 // @Accept json
 // @Produce json
 // @Param req body Request true "Name, surname and optional patronym"
-// @Success 200 {object} response.Response
-// @Failure 400 {object} response.Response
-// @Failure 500 {object} response.Response
+// @Success 201 {object} Response
+// @Header 201 {string} Location "/people/{id}"
+// @Failure 400 {object} response.Problem
+// @Failure 422 {object} response.Problem
+// @Failure 500 {object} response.Problem
 // @Router /people [post]
 func New(log *slog.Logger, peopleSaver PeopleSaver) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -48,7 +57,7 @@ func New(log *slog.Logger, peopleSaver PeopleSaver) http.HandlerFunc {
 		if err != nil {
 			log.Error("failed to decode request", sLogger.Error(err))
 
-			render.JSON(w, r, response.Error("internal server error"))
+			response.InvalidRequest("failed to decode request").Write(w, r)
 
 			return
 		}
@@ -62,49 +71,28 @@ func New(log *slog.Logger, peopleSaver PeopleSaver) http.HandlerFunc {
 
 			log.Error("invalid request", sLogger.Error(err))
 
-			render.JSON(w, r, response.ValidationError(validateErr))
-
-			return
-		}
-
-		age := api.GetAge(req.Name)
-		if age == 0 {
-			log.Error("failed to get age", sLogger.Error(err))
-
-			render.JSON(w, r, response.Error("internal server error"))
-
-			return
-		}
-
-		gender := api.GetGender(req.Name)
-		if gender == "" {
-			log.Error("failed to get gender", sLogger.Error(err))
-
-			render.JSON(w, r, response.Error("internal server error"))
+			response.ValidationError(validateErr).Write(w, r)
 
 			return
 		}
 
-		nationality := api.GetNationality(req.Name)
-		if nationality == "" {
-			log.Error("failed to get nationality", sLogger.Error(err))
-
-			render.JSON(w, r, response.Error("internal server error"))
-
-			return
-		}
-
-		id, err := peopleSaver.SavePeople(req.Name, req.Surname, req.Patronym, gender, nationality, age)
+		people, err := peopleSaver.SavePeople(r.Context(), req.Name, req.Surname, req.Patronym)
 		if err != nil {
 			log.Error("failed to save people", sLogger.Error(err))
 
-			render.JSON(w, r, response.Error("internal server error"))
+			response.Internal().Write(w, r)
 
 			return
 		}
 
-		log.Info("people saved", slog.Int64("id", id))
+		log.Info("people saved", slog.Int64("id", people.ID))
+
+		w.Header().Set("Location", "/people/"+strconv.FormatInt(people.ID, 10))
 
-		render.JSON(w, r, response.OK())
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, Response{
+			Response: response.OK(),
+			Data:     people,
+		})
 	}
 }
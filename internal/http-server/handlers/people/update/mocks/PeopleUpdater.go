@@ -2,24 +2,28 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
 
 // PeopleUpdater is an autogenerated mock type for the PeopleUpdater type
 type PeopleUpdater struct {
 	mock.Mock
 }
 
-// UpdatePeople provides a mock function with given fields: name, surname, patronym, gender, nationality, age, id
-func (_m *PeopleUpdater) UpdatePeople(name string, surname string, patronym string, gender string, nationality string, age int, id int64) error {
-	ret := _m.Called(name, surname, patronym, gender, nationality, age, id)
+// UpdatePeople provides a mock function with given fields: ctx, name, surname, patronym, gender, nationality, age, id
+func (_m *PeopleUpdater) UpdatePeople(ctx context.Context, name string, surname string, patronym string, gender string, nationality string, age int, id int64) error {
+	ret := _m.Called(ctx, name, surname, patronym, gender, nationality, age, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdatePeople")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string, string, string, int, int64) error); ok {
-		r0 = rf(name, surname, patronym, gender, nationality, age, id)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, string, int, int64) error); ok {
+		r0 = rf(ctx, name, surname, patronym, gender, nationality, age, id)
 	} else {
 		r0 = ret.Error(0)
 	}
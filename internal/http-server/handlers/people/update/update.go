@@ -1,6 +1,7 @@
 package update
 
 import (
+	"context"
 	"errors"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -25,7 +26,7 @@ type Request struct {
 
 //go:generate go run github.com/vektra/mockery/v2 --name=PeopleUpdater
 type PeopleUpdater interface {
-	UpdatePeople(name, surname, patronym, gender, nationality string, age int, id int64) error
+	UpdatePeople(ctx context.Context, name, surname, patronym, gender, nationality string, age int, id int64) error
 }
 
 // New @Summary Update person
@@ -36,9 +37,9 @@ type PeopleUpdater interface {
 // @Param id path int true "Person ID"
 // @Param req body Request true "Updated person info"
 // @Success 200 {object} response.Response
-// @Failure 400 {object} response.Response
-// @Failure 404 {object} response.Response
-// @Failure 500 {object} response.Response
+// @Failure 400 {object} response.Problem
+// @Failure 404 {object} response.Problem
+// @Failure 500 {object} response.Problem
 // @Router /people/{id} [put]
 // @Router /people/{id} [patch]
 func New(log *slog.Logger, peopleUpdater PeopleUpdater) http.HandlerFunc {
@@ -56,7 +57,7 @@ func New(log *slog.Logger, peopleUpdater PeopleUpdater) http.HandlerFunc {
 		if err != nil || id == 0 {
 			log.Info("id is invalid")
 
-			render.JSON(w, r, response.Error("invalid request"))
+			response.InvalidRequest("id is invalid").Write(w, r)
 
 			return
 		}
@@ -68,25 +69,25 @@ func New(log *slog.Logger, peopleUpdater PeopleUpdater) http.HandlerFunc {
 		if err = render.DecodeJSON(r.Body, &req); err != nil {
 			log.Error("failed to decode request", sLogger.Error(err))
 
-			render.JSON(w, r, response.Error("internal server error"))
+			response.InvalidRequest("failed to decode request").Write(w, r)
 
 			return
 		}
 
 		log.Info("request decoded", slog.Any("request", req))
 
-		err = peopleUpdater.UpdatePeople(req.Name, req.Surname, req.Patronym, req.Gender, req.Nationality, req.Age, id)
+		err = peopleUpdater.UpdatePeople(r.Context(), req.Name, req.Surname, req.Patronym, req.Gender, req.Nationality, req.Age, id)
 		if errors.Is(err, storage.ErrPeopleNotFound) {
 			log.Info("people not found", "id", id)
 
-			render.JSON(w, r, response.Error("not found"))
+			response.NotFound("people not found").Write(w, r)
 
 			return
 		}
 		if err != nil {
 			log.Error("failed to update people", sLogger.Error(err))
 
-			render.JSON(w, r, response.Error("internal server error"))
+			response.Internal().Write(w, r)
 
 			return
 		}
@@ -0,0 +1,78 @@
+package update_test
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"predictor/internal/http-server/handlers/people/update"
+	"predictor/internal/http-server/handlers/people/update/mocks"
+	"predictor/internal/lib/api/response"
+	"predictor/internal/storage"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestUpdateHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		body       string
+		mockErr    error
+		noCall     bool
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "updated", id: "1", body: `{"patronym":"Olegovich","age":40}`, wantStatus: http.StatusOK},
+		{name: "nothing to change", id: "1", body: `{}`, wantStatus: http.StatusOK},
+		{name: "not found", id: "2", body: `{"patronym":"Olegovich","age":40}`, mockErr: storage.ErrPeopleNotFound, wantStatus: http.StatusNotFound, wantCode: response.CodeNotFound},
+		{name: "nothing to change on missing person", id: "2", body: `{}`, mockErr: storage.ErrPeopleNotFound, wantStatus: http.StatusNotFound, wantCode: response.CodeNotFound},
+		{name: "storage failure", id: "3", body: `{"name":"Ivan"}`, mockErr: errors.New("connection reset"), wantStatus: http.StatusInternalServerError, wantCode: response.CodeInternal},
+		{name: "invalid id", id: "0", body: `{"name":"Ivan"}`, noCall: true, wantStatus: http.StatusBadRequest, wantCode: response.CodeInvalidRequest},
+		{name: "invalid body", id: "1", body: `{"age":"forty"}`, noCall: true, wantStatus: http.StatusBadRequest, wantCode: response.CodeInvalidRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updater := mocks.NewPeopleUpdater(t)
+
+			if !tt.noCall {
+				id, err := strconv.ParseInt(tt.id, 10, 64)
+				require.NoError(t, err)
+
+				var req update.Request
+				require.NoError(t, json.Unmarshal([]byte(tt.body), &req))
+
+				updater.On("UpdatePeople", mock.Anything, req.Name, req.Surname, req.Patronym, req.Gender, req.Nationality, req.Age, id).
+					Return(tt.mockErr).Once()
+			}
+
+			router := chi.NewRouter()
+			router.Patch("/people/{id}", update.New(slog.New(slog.DiscardHandler), updater))
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodPatch, "/people/"+tt.id, strings.NewReader(tt.body)))
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+
+			if tt.wantCode == "" {
+				return
+			}
+
+			assert.Equal(t, response.ContentTypeProblem, rec.Header().Get("Content-Type"))
+
+			var problem response.Problem
+
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+
+			assert.Equal(t, tt.wantStatus, problem.Status)
+			assert.Equal(t, tt.wantCode, problem.Code)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"predictor/internal/domain/models"
+)
+
+type AgeResponse struct {
+	Count int `json:"count"`
+	Age   int `json:"age"`
+}
+
+type Agify struct {
+	client  *Client
+	baseURL string
+	locale  string
+}
+
+func NewAgify(client *Client, baseURL, locale string) *Agify {
+	return &Agify{
+		client:  client,
+		baseURL: baseURL,
+		locale:  locale,
+	}
+}
+
+func (a *Agify) Age(ctx context.Context, name string) (models.AgePrediction, error) {
+	const op = "lib.api.Agify.Age"
+
+	var data AgeResponse
+
+	if err := a.client.GetJSON(ctx, a.baseURL, query(name, a.locale), &data); err != nil {
+		return models.AgePrediction{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if data.Age == 0 {
+		return models.AgePrediction{}, fmt.Errorf("%s: %w", op, ErrNoPrediction)
+	}
+
+	return models.AgePrediction{
+		Provider: "agify",
+		Age:      data.Age,
+		Count:    data.Count,
+	}, nil
+}
@@ -1,76 +1,237 @@
 package api
 
 import (
-	"encoding/json"
-	"io"
-	"log"
-	"net/http"
+	"context"
+	"errors"
+	"fmt"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/singleflight"
+	"predictor/internal/config"
+	"predictor/internal/domain/models"
+	"time"
 )
 
-type AgeResponse struct {
-	Age int `json:"age"`
+var (
+	ErrUnknownProvider = errors.New("unknown enrichment provider")
+	ErrNotSupported    = errors.New("provider does not support attribute")
+	ErrNoPrediction    = errors.New("provider returned no prediction")
+)
+
+type AgeProvider interface {
+	Age(ctx context.Context, name string) (models.AgePrediction, error)
 }
 
-type GenderResponse struct {
-	Gender string `json:"gender"`
+type GenderProvider interface {
+	Gender(ctx context.Context, name string) (models.GenderPrediction, error)
 }
 
-type NationalityResponse struct {
-	Country []struct {
-		CountryID string `json:"country_id"`
-	} `json:"country"`
+type NationalityProvider interface {
+	Nationality(ctx context.Context, name string) (models.NationalityPrediction, error)
 }
 
-func GetAge(name string) int {
-	resp, err := http.Get("https://api.agify.io/?name=" + name)
-	if err != nil {
-		log.Printf("Error getting age: %v", err)
-		return 0
+// Factory builds a provider from config. The returned value must implement
+// at least one of AgeProvider, GenderProvider or NationalityProvider.
+type Factory func(cfg config.Enrichment) any
+
+var registry = map[string]Factory{
+	"agify": func(cfg config.Enrichment) any {
+		return NewAgify(NewClient(policy(cfg)), cfg.AgifyURL, cfg.Locale)
+	},
+	"genderize": func(cfg config.Enrichment) any {
+		return NewGenderize(NewClient(policy(cfg)), cfg.GenderizeURL, cfg.Locale)
+	},
+	"nationalize": func(cfg config.Enrichment) any {
+		return NewNationalize(NewClient(policy(cfg)), cfg.NationalizeURL)
+	},
+	"static": func(cfg config.Enrichment) any {
+		return NewStatic(cfg.StaticAge, cfg.StaticGender, cfg.StaticNationality)
+	},
+}
+
+func policy(cfg config.Enrichment) Policy {
+	return Policy{
+		Retries:          cfg.Retries,
+		BackoffBase:      cfg.BackoffBase,
+		BackoffMax:       cfg.BackoffMax,
+		BreakerThreshold: cfg.BreakerThreshold,
+		BreakerCooldown:  cfg.BreakerCooldown,
 	}
-	defer resp.Body.Close()
+}
 
-	body, _ := io.ReadAll(resp.Body)
+// Register makes a provider available by name for selection through config.
+func Register(name string, factory Factory) {
+	registry[name] = factory
+}
 
-	var data AgeResponse
+// Timeouts bound every provider call separately, so one slow upstream
+// cannot hold the whole enrichment. Zero means no extra deadline.
+type Timeouts struct {
+	Age         time.Duration
+	Gender      time.Duration
+	Nationality time.Duration
+}
 
-	json.Unmarshal(body, &data)
+// longest is the deadline of a whole enrichment, as providers run in
+// parallel.
+func (t Timeouts) longest() time.Duration {
+	return max(t.Age, t.Gender, t.Nationality)
+}
 
-	return data.Age
+type Enricher struct {
+	age         AgeProvider
+	gender      GenderProvider
+	nationality NationalityProvider
+	timeouts    Timeouts
+	// inflight shares one lookup between concurrent calls for the same
+	// name, e.g. when a batch holds many namesakes.
+	inflight singleflight.Group
 }
 
-func GetGender(name string) string {
-	resp, err := http.Get("https://api.genderize.io/?name=" + name)
+// New builds the enricher selected by cfg. When cfg.CacheTTL is positive
+// every provider is put behind a Cache backed by store, which may be nil.
+func New(cfg config.Enrichment, store CacheStore) (*Enricher, error) {
+	const op = "lib.api.New"
+
+	age, err := lookup[AgeProvider](cfg, cfg.AgeProvider)
 	if err != nil {
-		log.Printf("Error getting gender: %v", err)
-		return ""
+		return nil, fmt.Errorf("%s: age: %w", op, err)
 	}
-	defer resp.Body.Close()
 
-	body, _ := io.ReadAll(resp.Body)
+	gender, err := lookup[GenderProvider](cfg, cfg.GenderProvider)
+	if err != nil {
+		return nil, fmt.Errorf("%s: gender: %w", op, err)
+	}
 
-	var data GenderResponse
+	nationality, err := lookup[NationalityProvider](cfg, cfg.NationalityProvider)
+	if err != nil {
+		return nil, fmt.Errorf("%s: nationality: %w", op, err)
+	}
+
+	if cfg.CacheTTL > 0 {
+		cache := NewCache(store, cfg.CacheSize, cfg.CacheTTL, cfg.Locale)
 
-	json.Unmarshal(body, &data)
+		age = cachedAge{next: age, cache: cache}
+		gender = cachedGender{next: gender, cache: cache}
+		nationality = cachedNationality{next: nationality, cache: cache}
+	}
 
-	return data.Gender
+	return NewEnricher(age, gender, nationality, Timeouts{
+		Age:         cfg.AgeTimeout,
+		Gender:      cfg.GenderTimeout,
+		Nationality: cfg.NationalityTimeout,
+	}), nil
 }
 
-func GetNationality(name string) string {
-	resp, err := http.Get("https://api.nationalize.io/?name=" + name)
-	if err != nil {
-		log.Printf("Error getting nationality: %v", err)
-		return ""
+func NewEnricher(age AgeProvider, gender GenderProvider, nationality NationalityProvider, timeouts Timeouts) *Enricher {
+	return &Enricher{
+		age:         age,
+		gender:      gender,
+		nationality: nationality,
+		timeouts:    timeouts,
 	}
-	defer resp.Body.Close()
+}
 
-	body, _ := io.ReadAll(resp.Body)
+func lookup[T any](cfg config.Enrichment, name string) (T, error) {
+	var zero T
 
-	var data NationalityResponse
+	factory, ok := registry[name]
+	if !ok {
+		return zero, fmt.Errorf("%w: %q", ErrUnknownProvider, name)
+	}
 
-	json.Unmarshal(body, &data)
+	provider, ok := factory(cfg).(T)
+	if !ok {
+		return zero, fmt.Errorf("%w: %q", ErrNotSupported, name)
+	}
 
-	if len(data.Country) > 0 {
-		return data.Country[0].CountryID
+	return provider, nil
+}
+
+// Enrich queries all providers concurrently. The first failure cancels the
+// remaining calls. Concurrent calls for the same name share the result. The
+// shared lookup does not depend on any one caller: it runs under its own
+// deadline, and a caller whose ctx is done stops waiting without failing the
+// others.
+func (e *Enricher) Enrich(ctx context.Context, name string) (models.Prediction, error) {
+	ch := e.inflight.DoChan(name, func() (any, error) {
+		shared, cancel := withTimeout(context.WithoutCancel(ctx), e.timeouts.longest())
+		defer cancel()
+
+		return e.enrich(shared, name)
+	})
+
+	select {
+	case <-ctx.Done():
+		return models.Prediction{}, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return models.Prediction{}, res.Err
+		}
+
+		return res.Val.(models.Prediction), nil
 	}
-	return ""
+}
+
+func (e *Enricher) enrich(ctx context.Context, name string) (models.Prediction, error) {
+	const op = "lib.api.Enrich"
+
+	var prediction models.Prediction
+
+	g, ctx := errgroup.WithContext(ctx)
+
+	g.Go(func() error {
+		ctx, cancel := withTimeout(ctx, e.timeouts.Age)
+		defer cancel()
+
+		age, err := e.age.Age(ctx, name)
+		if err != nil {
+			return fmt.Errorf("age: %w", err)
+		}
+
+		prediction.Age = age
+
+		return nil
+	})
+
+	g.Go(func() error {
+		ctx, cancel := withTimeout(ctx, e.timeouts.Gender)
+		defer cancel()
+
+		gender, err := e.gender.Gender(ctx, name)
+		if err != nil {
+			return fmt.Errorf("gender: %w", err)
+		}
+
+		prediction.Gender = gender
+
+		return nil
+	})
+
+	g.Go(func() error {
+		ctx, cancel := withTimeout(ctx, e.timeouts.Nationality)
+		defer cancel()
+
+		nationality, err := e.nationality.Nationality(ctx, name)
+		if err != nil {
+			return fmt.Errorf("nationality: %w", err)
+		}
+
+		prediction.Nationality = nationality
+
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return models.Prediction{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return prediction, nil
+}
+
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, timeout)
 }
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"predictor/internal/domain/models"
+	"predictor/internal/lib/lru"
+	"predictor/internal/storage"
+	"strings"
+	"time"
+)
+
+const (
+	kindAge         = "age"
+	kindGender      = "gender"
+	kindNationality = "nationality"
+)
+
+// cacheStats is published on the admin /debug/vars as enrichment_cache.
+var cacheStats = expvar.NewMap("enrichment_cache")
+
+type CacheStore interface {
+	GetCached(ctx context.Context, kind, name, locale string) ([]byte, error)
+	SaveCached(ctx context.Context, kind, name, locale string, payload []byte, ttl time.Duration) error
+}
+
+type cacheKey struct {
+	kind   string
+	name   string
+	locale string
+}
+
+// Cache answers repeated names from an in-memory LRU first and the
+// persistent store second, and only then asks the provider.
+type Cache struct {
+	store  CacheStore
+	memory *lru.Cache[cacheKey, any]
+	ttl    time.Duration
+	locale string
+}
+
+// NewCache creates a cache. A nil store keeps only the in-memory layer and
+// a zero size disables it.
+func NewCache(store CacheStore, size int, ttl time.Duration, locale string) *Cache {
+	return &Cache{
+		store:  store,
+		memory: lru.New[cacheKey, any](size),
+		ttl:    ttl,
+		locale: locale,
+	}
+}
+
+func cached[T any](ctx context.Context, c *Cache, kind, name string, fetch func(context.Context, string) (T, error)) (T, error) {
+	key := cacheKey{
+		kind:   kind,
+		name:   strings.ToLower(strings.TrimSpace(name)),
+		locale: c.locale,
+	}
+
+	if v, ok := c.memory.Get(key); ok {
+		cacheStats.Add(kind+"_memory_hits", 1)
+
+		return v.(T), nil
+	}
+
+	if c.store != nil {
+		payload, err := c.store.GetCached(ctx, key.kind, key.name, key.locale)
+		if err == nil {
+			var v T
+
+			if err = json.Unmarshal(payload, &v); err == nil {
+				cacheStats.Add(kind+"_store_hits", 1)
+				c.memory.Add(key, v, c.ttl)
+
+				return v, nil
+			}
+		}
+		if !errors.Is(err, storage.ErrCacheMiss) {
+			cacheStats.Add(kind+"_errors", 1)
+		}
+	}
+
+	cacheStats.Add(kind+"_misses", 1)
+
+	v, err := fetch(ctx, name)
+	if err != nil {
+		return v, err
+	}
+
+	c.memory.Add(key, v, c.ttl)
+
+	if c.store != nil {
+		payload, err := json.Marshal(v)
+		if err == nil {
+			err = c.store.SaveCached(ctx, key.kind, key.name, key.locale, payload, c.ttl)
+		}
+		if err != nil {
+			cacheStats.Add(kind+"_errors", 1)
+		}
+	}
+
+	return v, nil
+}
+
+type cachedAge struct {
+	next  AgeProvider
+	cache *Cache
+}
+
+func (p cachedAge) Age(ctx context.Context, name string) (models.AgePrediction, error) {
+	return cached(ctx, p.cache, kindAge, name, p.next.Age)
+}
+
+type cachedGender struct {
+	next  GenderProvider
+	cache *Cache
+}
+
+func (p cachedGender) Gender(ctx context.Context, name string) (models.GenderPrediction, error) {
+	return cached(ctx, p.cache, kindGender, name, p.next.Gender)
+}
+
+type cachedNationality struct {
+	next  NationalityProvider
+	cache *Cache
+}
+
+func (p cachedNationality) Nationality(ctx context.Context, name string) (models.NationalityPrediction, error) {
+	return cached(ctx, p.cache, kindNationality, name, p.next.Nationality)
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"predictor/internal/domain/models"
+)
+
+type GenderResponse struct {
+	Count       int     `json:"count"`
+	Gender      string  `json:"gender"`
+	Probability float64 `json:"probability"`
+}
+
+type Genderize struct {
+	client  *Client
+	baseURL string
+	locale  string
+}
+
+func NewGenderize(client *Client, baseURL, locale string) *Genderize {
+	return &Genderize{
+		client:  client,
+		baseURL: baseURL,
+		locale:  locale,
+	}
+}
+
+func (g *Genderize) Gender(ctx context.Context, name string) (models.GenderPrediction, error) {
+	const op = "lib.api.Genderize.Gender"
+
+	var data GenderResponse
+
+	if err := g.client.GetJSON(ctx, g.baseURL, query(name, g.locale), &data); err != nil {
+		return models.GenderPrediction{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if data.Gender == "" {
+		return models.GenderPrediction{}, fmt.Errorf("%s: %w", op, ErrNoPrediction)
+	}
+
+	return models.GenderPrediction{
+		Provider:    "genderize",
+		Gender:      data.Gender,
+		Probability: data.Probability,
+		Count:       data.Count,
+	}, nil
+}
@@ -0,0 +1,202 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"predictor/internal/lib/breaker"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrCircuitOpen = errors.New("provider circuit is open")
+	ErrRateLimited = errors.New("provider rate limit exceeded")
+	ErrUpstream    = errors.New("provider is unavailable")
+	ErrBadResponse = errors.New("provider rejected request")
+)
+
+// Policy configures how a Client retries and when it stops calling a
+// failing provider.
+type Policy struct {
+	Retries          int
+	BackoffBase      time.Duration
+	BackoffMax       time.Duration
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+// Client performs provider requests with retries and a circuit breaker.
+// Every provider should own its Client so breakers trip independently.
+type Client struct {
+	http    *http.Client
+	policy  Policy
+	breaker *breaker.Breaker
+}
+
+func NewClient(policy Policy) *Client {
+	return &Client{
+		http:    http.DefaultClient,
+		policy:  policy,
+		breaker: breaker.New(policy.BreakerThreshold, policy.BreakerCooldown),
+	}
+}
+
+// statusError describes a non-2xx answer. Transient ones are worth retrying.
+type statusError struct {
+	err        error
+	code       int
+	retryAfter time.Duration
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("%s: status code %d", e.err, e.code)
+}
+
+func (e *statusError) Unwrap() error {
+	return e.err
+}
+
+func (c *Client) GetJSON(ctx context.Context, baseURL string, query url.Values, dst any) error {
+	if err := c.breaker.Allow(); err != nil {
+		return ErrCircuitOpen
+	}
+
+	var err error
+
+	for attempt := 0; ; attempt++ {
+		err = c.get(ctx, baseURL, query, dst)
+		if err == nil {
+			c.breaker.Success()
+
+			return nil
+		}
+
+		if ctx.Err() != nil {
+			c.breaker.Release()
+
+			return err
+		}
+
+		if !transient(err) {
+			// The provider answered, so it is up even if it disliked the request.
+			c.breaker.Success()
+
+			return err
+		}
+
+		if attempt >= c.policy.Retries {
+			break
+		}
+
+		delay := c.backoff(attempt)
+
+		var se *statusError
+		if errors.As(err, &se) && se.retryAfter > 0 {
+			delay = se.retryAfter
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			break
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			c.breaker.Release()
+
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	c.breaker.Failure()
+
+	return err
+}
+
+func (c *Client) get(ctx context.Context, baseURL string, query url.Values, dst any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(baseURL, "/")+"/?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUpstream, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return &statusError{
+			err:        ErrRateLimited,
+			code:       resp.StatusCode,
+			retryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return &statusError{err: ErrUpstream, code: resp.StatusCode}
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return &statusError{err: ErrBadResponse, code: resp.StatusCode}
+	}
+
+	if err = json.NewDecoder(resp.Body).Decode(dst); err != nil {
+		return fmt.Errorf("%w: %w", ErrBadResponse, err)
+	}
+
+	return nil
+}
+
+func transient(err error) bool {
+	return errors.Is(err, ErrRateLimited) || errors.Is(err, ErrUpstream)
+}
+
+// backoff returns a full-jitter exponential delay for the given attempt.
+func (c *Client) backoff(attempt int) time.Duration {
+	if c.policy.BackoffBase <= 0 {
+		return 0
+	}
+
+	d := c.policy.BackoffBase << attempt
+	if c.policy.BackoffMax > 0 && (d <= 0 || d > c.policy.BackoffMax) {
+		d = c.policy.BackoffMax
+	}
+	if d <= 0 {
+		d = c.policy.BackoffBase
+	}
+
+	return rand.N(d) + 1
+}
+
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(v); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t)
+	}
+
+	return 0
+}
+
+// query builds the lookup parameters. agify and genderize narrow their
+// answer to a country when country_id is passed.
+func query(name, locale string) url.Values {
+	q := url.Values{"name": {name}}
+	if locale != "" {
+		q.Set("country_id", locale)
+	}
+
+	return q
+}
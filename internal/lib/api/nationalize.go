@@ -0,0 +1,56 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"predictor/internal/domain/models"
+)
+
+type NationalityResponse struct {
+	Count   int `json:"count"`
+	Country []struct {
+		CountryID   string  `json:"country_id"`
+		Probability float64 `json:"probability"`
+	} `json:"country"`
+}
+
+type Nationalize struct {
+	client  *Client
+	baseURL string
+}
+
+func NewNationalize(client *Client, baseURL string) *Nationalize {
+	return &Nationalize{
+		client:  client,
+		baseURL: baseURL,
+	}
+}
+
+func (n *Nationalize) Nationality(ctx context.Context, name string) (models.NationalityPrediction, error) {
+	const op = "lib.api.Nationalize.Nationality"
+
+	var data NationalityResponse
+
+	if err := n.client.GetJSON(ctx, n.baseURL, url.Values{"name": {name}}, &data); err != nil {
+		return models.NationalityPrediction{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(data.Country) == 0 {
+		return models.NationalityPrediction{}, fmt.Errorf("%s: %w", op, ErrNoPrediction)
+	}
+
+	countries := make([]models.CountryProbability, 0, len(data.Country))
+	for _, c := range data.Country {
+		countries = append(countries, models.CountryProbability{
+			CountryID:   c.CountryID,
+			Probability: c.Probability,
+		})
+	}
+
+	return models.NationalityPrediction{
+		Provider:  "nationalize",
+		Countries: countries,
+		Count:     data.Count,
+	}, nil
+}
@@ -1,19 +1,31 @@
 package response
 
 import (
+	"encoding/json"
 	"fmt"
+	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-playground/validator/v10"
-	"strings"
+	"net/http"
 )
 
 const (
-	StatusOK    = "OK"
-	StatusError = "Error"
+	StatusOK = "OK"
+)
+
+// ContentTypeProblem is the media type of error bodies, see RFC 7807.
+const ContentTypeProblem = "application/problem+json"
+
+const (
+	CodeInvalidRequest   = "invalid_request"
+	CodeValidationFailed = "validation_failed"
+	CodeNotFound         = "not_found"
+	CodeConflict         = "conflict"
+	CodeUpstreamFailed   = "upstream_failed"
+	CodeInternal         = "internal_error"
 )
 
 type Response struct {
 	Status string `json:"status"`
-	Error  string `json:"error,omitempty"`
 }
 
 func OK() Response {
@@ -22,29 +34,85 @@ func OK() Response {
 	}
 }
 
-func Error(msg string) Response {
-	return Response{
-		Status: StatusError,
-		Error:  msg,
+// Problem is the body of every error response. Code is stable and meant for
+// machines, Detail is meant for humans.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Code      string       `json:"code"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+	Fields    []FieldError `json:"fields,omitempty"`
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func NewProblem(status int, code, detail string) Problem {
+	return Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Code:   code,
+		Detail: detail,
 	}
 }
 
-func ValidationError(errs validator.ValidationErrors) Response {
-	var errMessages []string
+func InvalidRequest(detail string) Problem {
+	return NewProblem(http.StatusBadRequest, CodeInvalidRequest, detail)
+}
+
+func NotFound(detail string) Problem {
+	return NewProblem(http.StatusNotFound, CodeNotFound, detail)
+}
+
+func Conflict(detail string) Problem {
+	return NewProblem(http.StatusConflict, CodeConflict, detail)
+}
+
+func UpstreamFailed(detail string) Problem {
+	return NewProblem(http.StatusBadGateway, CodeUpstreamFailed, detail)
+}
+
+func Internal() Problem {
+	return NewProblem(http.StatusInternalServerError, CodeInternal, "internal server error")
+}
+
+func ValidationError(errs validator.ValidationErrors) Problem {
+	p := NewProblem(http.StatusUnprocessableEntity, CodeValidationFailed, "request is not valid")
 
 	for _, err := range errs {
+		var msg string
+
 		switch err.ActualTag() {
 		case "required":
-			errMessages = append(errMessages, fmt.Sprintf("field %s is a required field", err.Field()))
+			msg = fmt.Sprintf("field %s is a required field", err.Field())
 		case "url":
-			errMessages = append(errMessages, fmt.Sprintf("field %s is not a valid URL", err.Field()))
+			msg = fmt.Sprintf("field %s is not a valid URL", err.Field())
 		default:
-			errMessages = append(errMessages, fmt.Sprintf("field %s is not valid", err.Field()))
+			msg = fmt.Sprintf("field %s is not valid", err.Field())
 		}
-	}
 
-	return Response{
-		Status: StatusError,
-		Error:  strings.Join(errMessages, ", "),
+		p.Fields = append(p.Fields, FieldError{
+			Field:   err.Field(),
+			Message: msg,
+		})
 	}
+
+	return p
+}
+
+// Write sends p with its status code, filling in the request path and id.
+func (p Problem) Write(w http.ResponseWriter, r *http.Request) {
+	p.Instance = r.URL.Path
+	p.RequestID = middleware.GetReqID(r.Context())
+
+	w.Header().Set("Content-Type", ContentTypeProblem)
+	w.WriteHeader(p.Status)
+
+	_ = json.NewEncoder(w).Encode(p)
 }
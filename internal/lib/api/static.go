@@ -0,0 +1,37 @@
+package api
+
+import (
+	"context"
+	"predictor/internal/domain/models"
+)
+
+// Static answers every name with the same configured values. It is meant for
+// local runs and tests where the public APIs must not be reached.
+type Static struct {
+	age         int
+	gender      string
+	nationality string
+}
+
+func NewStatic(age int, gender, nationality string) *Static {
+	return &Static{
+		age:         age,
+		gender:      gender,
+		nationality: nationality,
+	}
+}
+
+func (s *Static) Age(_ context.Context, _ string) (models.AgePrediction, error) {
+	return models.AgePrediction{Provider: "static", Age: s.age}, nil
+}
+
+func (s *Static) Gender(_ context.Context, _ string) (models.GenderPrediction, error) {
+	return models.GenderPrediction{Provider: "static", Gender: s.gender, Probability: 1}, nil
+}
+
+func (s *Static) Nationality(_ context.Context, _ string) (models.NationalityPrediction, error) {
+	return models.NationalityPrediction{
+		Provider:  "static",
+		Countries: []models.CountryProbability{{CountryID: s.nationality, Probability: 1}},
+	}, nil
+}
@@ -0,0 +1,401 @@
+package peopleio
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"predictor/internal/domain/models"
+)
+
+// The Parquet writer is kept to what an export needs: one flat schema,
+// PLAIN encoded uncompressed pages and a footer in the Thrift compact
+// protocol. Rows are buffered and written a row group at a time.
+
+const parquetMagic = "PAR1"
+
+// parquetRowGroup is how many rows are buffered before they are written.
+const parquetRowGroup = 10000
+
+// Parquet enum values, see parquet.thrift.
+const (
+	parquetInt32     = 1
+	parquetInt64     = 2
+	parquetByteArray = 6
+
+	parquetRequired = 0
+	parquetOptional = 1
+
+	parquetConvertedUTF8            = 0
+	parquetConvertedTimestampMicros = 10
+
+	parquetEncodingPlain = 0
+	parquetEncodingRLE   = 3
+
+	parquetCodecUncompressed = 0
+	parquetDataPage          = 0
+)
+
+// parquetColumn describes a column and takes its value from a person. ok is
+// false for a missing value of an optional column, stored as null.
+type parquetColumn struct {
+	name      string
+	typ       int32
+	optional  bool
+	converted int32
+	value     func(p models.People) (v any, ok bool)
+}
+
+func optionalText(v string) (any, bool) { return v, v != "" }
+
+var parquetColumns = []parquetColumn{
+	{name: "id", typ: parquetInt64, converted: -1, value: func(p models.People) (any, bool) { return p.ID, true }},
+	{name: "name", typ: parquetByteArray, converted: parquetConvertedUTF8, value: func(p models.People) (any, bool) { return p.Name, true }},
+	{name: "surname", typ: parquetByteArray, converted: parquetConvertedUTF8, value: func(p models.People) (any, bool) { return p.Surname, true }},
+	{name: "patronym", typ: parquetByteArray, optional: true, converted: parquetConvertedUTF8, value: func(p models.People) (any, bool) { return optionalText(p.Patronymic) }},
+	{name: "age", typ: parquetInt32, optional: true, converted: -1, value: func(p models.People) (any, bool) { return int32(p.Age), p.Age != 0 }},
+	{name: "gender", typ: parquetByteArray, optional: true, converted: parquetConvertedUTF8, value: func(p models.People) (any, bool) { return optionalText(p.Gender) }},
+	{name: "nationality", typ: parquetByteArray, optional: true, converted: parquetConvertedUTF8, value: func(p models.People) (any, bool) { return optionalText(p.Nationality) }},
+	{name: "enrichment_status", typ: parquetByteArray, converted: parquetConvertedUTF8, value: func(p models.People) (any, bool) { return p.EnrichmentStatus, true }},
+	{name: "created_at", typ: parquetInt64, optional: true, converted: parquetConvertedTimestampMicros, value: func(p models.People) (any, bool) {
+		return p.CreatedAt.UnixMicro(), !p.CreatedAt.IsZero()
+	}},
+}
+
+// parquetWriter writes a Parquet file. Flush writes the buffered rows and the
+// footer, so it must be called once, after the last Write.
+type parquetWriter struct {
+	w      *bufio.Writer
+	offset int64
+	rows   []models.People
+	total  int64
+	groups [][]byte
+}
+
+func newParquetWriter(w io.Writer) *parquetWriter {
+	return &parquetWriter{w: bufio.NewWriter(w)}
+}
+
+func (pw *parquetWriter) Write(p models.People) error {
+	pw.rows = append(pw.rows, p)
+
+	if len(pw.rows) < parquetRowGroup {
+		return nil
+	}
+
+	return pw.writeRowGroup()
+}
+
+func (pw *parquetWriter) Flush() error {
+	if err := pw.writeRowGroup(); err != nil {
+		return err
+	}
+
+	if err := pw.start(); err != nil {
+		return err
+	}
+
+	footer := pw.fileMetaData()
+
+	var size [4]byte
+	binary.LittleEndian.PutUint32(size[:], uint32(len(footer)))
+
+	if err := pw.write(footer, size[:], []byte(parquetMagic)); err != nil {
+		return err
+	}
+
+	return pw.w.Flush()
+}
+
+func (pw *parquetWriter) write(chunks ...[]byte) error {
+	for _, c := range chunks {
+		n, err := pw.w.Write(c)
+		pw.offset += int64(n)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// start writes the leading magic before anything else.
+func (pw *parquetWriter) start() error {
+	if pw.offset > 0 {
+		return nil
+	}
+
+	return pw.write([]byte(parquetMagic))
+}
+
+// writeRowGroup writes the buffered rows as one row group with a single data
+// page per column and remembers its metadata for the footer.
+func (pw *parquetWriter) writeRowGroup() error {
+	if len(pw.rows) == 0 {
+		return nil
+	}
+
+	if err := pw.start(); err != nil {
+		return err
+	}
+
+	var chunks [][]byte
+	var groupSize int64
+
+	for _, col := range parquetColumns {
+		page := col.page(pw.rows)
+
+		header := new(compact)
+		header.i32(1, parquetDataPage)
+		header.i32(2, int32(len(page)))
+		header.i32(3, int32(len(page)))
+
+		dataHeader := new(compact)
+		dataHeader.i32(1, int32(len(pw.rows)))
+		dataHeader.i32(2, parquetEncodingPlain)
+		dataHeader.i32(3, parquetEncodingRLE)
+		dataHeader.i32(4, parquetEncodingRLE)
+		header.structure(5, dataHeader)
+
+		headerBytes := header.end()
+		offset := pw.offset
+		size := int64(len(headerBytes) + len(page))
+
+		if err := pw.write(headerBytes, page); err != nil {
+			return err
+		}
+
+		meta := new(compact)
+		meta.i32(1, col.typ)
+		meta.list(2, compactI32, [][]byte{varint(zigzag(parquetEncodingPlain)), varint(zigzag(parquetEncodingRLE))})
+		meta.list(3, compactBinary, [][]byte{binaryValue([]byte(col.name))})
+		meta.i32(4, parquetCodecUncompressed)
+		meta.i64(5, int64(len(pw.rows)))
+		meta.i64(6, size)
+		meta.i64(7, size)
+		meta.i64(9, offset)
+
+		chunk := new(compact)
+		chunk.i64(2, offset)
+		chunk.structure(3, meta)
+
+		chunks = append(chunks, chunk.end())
+		groupSize += size
+	}
+
+	group := new(compact)
+	group.list(1, compactStruct, chunks)
+	group.i64(2, groupSize)
+	group.i64(3, int64(len(pw.rows)))
+
+	pw.groups = append(pw.groups, group.end())
+	pw.total += int64(len(pw.rows))
+	pw.rows = pw.rows[:0]
+
+	return nil
+}
+
+func (pw *parquetWriter) fileMetaData() []byte {
+	root := new(compact)
+	root.binary(4, []byte("schema"))
+	root.i32(5, int32(len(parquetColumns)))
+
+	schema := [][]byte{root.end()}
+
+	for _, col := range parquetColumns {
+		schema = append(schema, col.schemaElement())
+	}
+
+	meta := new(compact)
+	meta.i32(1, 1)
+	meta.list(2, compactStruct, schema)
+	meta.i64(3, pw.total)
+	meta.list(4, compactStruct, pw.groups)
+	meta.binary(6, []byte("predictor"))
+
+	return meta.end()
+}
+
+func (c parquetColumn) schemaElement() []byte {
+	repetition := int32(parquetRequired)
+	if c.optional {
+		repetition = parquetOptional
+	}
+
+	e := new(compact)
+	e.i32(1, c.typ)
+	e.i32(3, repetition)
+	e.binary(4, []byte(c.name))
+
+	if c.converted >= 0 {
+		e.i32(6, c.converted)
+	}
+
+	// The logical type says the same as the converted type for newer readers.
+	switch c.converted {
+	case parquetConvertedUTF8:
+		e.structure(10, new(compact).with(func(l *compact) { l.structure(1, new(compact)) }))
+	case parquetConvertedTimestampMicros:
+		e.structure(10, new(compact).with(func(l *compact) {
+			l.structure(8, new(compact).with(func(ts *compact) {
+				ts.bool(1, true)
+				ts.structure(2, new(compact).with(func(u *compact) { u.structure(2, new(compact)) }))
+			}))
+		}))
+	}
+
+	return e.end()
+}
+
+// page encodes the column of rows as a v1 data page: definition levels for
+// an optional column, then the present values.
+func (c parquetColumn) page(rows []models.People) []byte {
+	var page, levels []byte
+	var run byte
+	var runLen int
+
+	// Levels are written as RLE runs of bit width 1.
+	flush := func() {
+		if runLen > 0 {
+			levels = append(levels, varint(uint64(runLen)<<1)...)
+			levels = append(levels, run)
+		}
+	}
+
+	for _, p := range rows {
+		v, ok := c.value(p)
+
+		if c.optional {
+			var level byte
+			if ok {
+				level = 1
+			}
+
+			if level != run || runLen == 0 {
+				flush()
+				run, runLen = level, 0
+			}
+			runLen++
+		}
+
+		if !ok {
+			continue
+		}
+
+		switch v := v.(type) {
+		case int32:
+			page = binary.LittleEndian.AppendUint32(page, uint32(v))
+		case int64:
+			page = binary.LittleEndian.AppendUint64(page, uint64(v))
+		case string:
+			page = binary.LittleEndian.AppendUint32(page, uint32(len(v)))
+			page = append(page, v...)
+		}
+	}
+
+	if !c.optional {
+		return page
+	}
+
+	flush()
+
+	out := binary.LittleEndian.AppendUint32(nil, uint32(len(levels)))
+	out = append(out, levels...)
+
+	return append(out, page...)
+}
+
+// Thrift compact protocol types.
+const (
+	compactTrue   = 1
+	compactFalse  = 2
+	compactI32    = 5
+	compactI64    = 6
+	compactBinary = 8
+	compactList   = 9
+	compactStruct = 12
+)
+
+// compact encodes one Thrift struct in the compact protocol.
+type compact struct {
+	b    []byte
+	last int16
+}
+
+func (c *compact) with(fn func(*compact)) *compact {
+	fn(c)
+
+	return c
+}
+
+func (c *compact) field(id int16, typ byte) {
+	if delta := id - c.last; delta > 0 && delta <= 15 {
+		c.b = append(c.b, byte(delta)<<4|typ)
+	} else {
+		c.b = append(c.b, typ)
+		c.b = append(c.b, varint(zigzag(int64(id)))...)
+	}
+
+	c.last = id
+}
+
+func (c *compact) bool(id int16, v bool) {
+	if v {
+		c.field(id, compactTrue)
+	} else {
+		c.field(id, compactFalse)
+	}
+}
+
+func (c *compact) i32(id int16, v int32) {
+	c.field(id, compactI32)
+	c.b = append(c.b, varint(zigzag(int64(v)))...)
+}
+
+func (c *compact) i64(id int16, v int64) {
+	c.field(id, compactI64)
+	c.b = append(c.b, varint(zigzag(v))...)
+}
+
+func (c *compact) binary(id int16, v []byte) {
+	c.field(id, compactBinary)
+	c.b = append(c.b, binaryValue(v)...)
+}
+
+func (c *compact) structure(id int16, s *compact) {
+	c.field(id, compactStruct)
+	c.b = append(c.b, s.end()...)
+}
+
+// list writes a list of already encoded elements of one type.
+func (c *compact) list(id int16, typ byte, elems [][]byte) {
+	c.field(id, compactList)
+
+	if len(elems) < 15 {
+		c.b = append(c.b, byte(len(elems))<<4|typ)
+	} else {
+		c.b = append(c.b, 0xf0|typ)
+		c.b = append(c.b, varint(uint64(len(elems)))...)
+	}
+
+	for _, e := range elems {
+		c.b = append(c.b, e...)
+	}
+}
+
+// end returns the struct with its stop byte.
+func (c *compact) end() []byte {
+	return append(c.b, 0)
+}
+
+func binaryValue(v []byte) []byte {
+	return append(varint(uint64(len(v))), v...)
+}
+
+func zigzag(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63)
+}
+
+func varint(v uint64) []byte {
+	return binary.AppendUvarint(nil, v)
+}
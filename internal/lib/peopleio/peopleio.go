@@ -0,0 +1,306 @@
+// Package peopleio reads and writes people as CSV or NDJSON, and writes them
+// as Parquet.
+package peopleio
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"predictor/internal/domain/models"
+	"strconv"
+	"time"
+)
+
+const (
+	FormatCSV     = "csv"
+	FormatNDJSON  = "ndjson"
+	FormatParquet = "parquet"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported format")
+	ErrInvalidRecord     = errors.New("invalid record")
+)
+
+// columns are the CSV header, in order. Reading only needs name and surname,
+// the other columns may be missing or come in any order.
+var columns = []string{"id", "name", "surname", "patronym", "age", "gender", "nationality", "enrichment_status", "created_at"}
+
+// record is the NDJSON shape of a person, keyed like the CSV columns.
+type record struct {
+	ID               int64     `json:"id,omitempty"`
+	Name             string    `json:"name"`
+	Surname          string    `json:"surname"`
+	Patronym         string    `json:"patronym,omitempty"`
+	Age              int       `json:"age,omitempty"`
+	Gender           string    `json:"gender,omitempty"`
+	Nationality      string    `json:"nationality,omitempty"`
+	EnrichmentStatus string    `json:"enrichment_status,omitempty"`
+	CreatedAt        time.Time `json:"created_at,omitzero"`
+}
+
+func toRecord(p models.People) record {
+	return record{
+		ID:               p.ID,
+		Name:             p.Name,
+		Surname:          p.Surname,
+		Patronym:         p.Patronymic,
+		Age:              p.Age,
+		Gender:           p.Gender,
+		Nationality:      p.Nationality,
+		EnrichmentStatus: p.EnrichmentStatus,
+		CreatedAt:        p.CreatedAt,
+	}
+}
+
+func (r record) people() models.People {
+	return models.People{
+		ID:               r.ID,
+		Name:             r.Name,
+		Surname:          r.Surname,
+		Patronymic:       r.Patronym,
+		Age:              r.Age,
+		Gender:           r.Gender,
+		Nationality:      r.Nationality,
+		EnrichmentStatus: r.EnrichmentStatus,
+		CreatedAt:        r.CreatedAt,
+	}
+}
+
+// ContentType returns the media type of the format.
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv"
+	case FormatParquet:
+		return "application/vnd.apache.parquet"
+	default:
+		return "application/x-ndjson"
+	}
+}
+
+type Writer interface {
+	Write(p models.People) error
+	// Flush writes any buffered data to the underlying writer.
+	Flush() error
+}
+
+func NewWriter(w io.Writer, format string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	case FormatNDJSON:
+		bw := bufio.NewWriter(w)
+
+		return &ndjsonWriter{w: bw, enc: json.NewEncoder(bw)}, nil
+	case FormatParquet:
+		return newParquetWriter(w), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+}
+
+type csvWriter struct {
+	w      *csv.Writer
+	header bool
+}
+
+func (c *csvWriter) Write(p models.People) error {
+	if !c.header {
+		if err := c.w.Write(columns); err != nil {
+			return err
+		}
+		c.header = true
+	}
+
+	age := ""
+	if p.Age != 0 {
+		age = strconv.Itoa(p.Age)
+	}
+
+	createdAt := ""
+	if !p.CreatedAt.IsZero() {
+		createdAt = p.CreatedAt.Format(time.RFC3339Nano)
+	}
+
+	return c.w.Write([]string{
+		strconv.FormatInt(p.ID, 10),
+		p.Name,
+		p.Surname,
+		p.Patronymic,
+		age,
+		p.Gender,
+		p.Nationality,
+		p.EnrichmentStatus,
+		createdAt,
+	})
+}
+
+func (c *csvWriter) Flush() error {
+	// An empty export still gets its header.
+	if !c.header {
+		if err := c.w.Write(columns); err != nil {
+			return err
+		}
+		c.header = true
+	}
+
+	c.w.Flush()
+
+	return c.w.Error()
+}
+
+type ndjsonWriter struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+func (n *ndjsonWriter) Write(p models.People) error {
+	return n.enc.Encode(toRecord(p))
+}
+
+func (n *ndjsonWriter) Flush() error {
+	return n.w.Flush()
+}
+
+// Reader reads people one by one and returns io.EOF at the end. A record
+// that cannot be read is reported with ErrInvalidRecord, after which
+// reading may go on.
+type Reader interface {
+	Read() (models.People, error)
+	// Line returns the input line of the last record read.
+	Line() int
+}
+
+func NewReader(r io.Reader, format string) (Reader, error) {
+	switch format {
+	case FormatCSV:
+		cr := csv.NewReader(r)
+		cr.FieldsPerRecord = -1
+
+		header, err := cr.Read()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read header: %w", err)
+		}
+
+		index := make(map[string]int, len(header))
+		for i, name := range header {
+			index[name] = i
+		}
+
+		for _, required := range []string{"name", "surname"} {
+			if _, ok := index[required]; !ok {
+				return nil, fmt.Errorf("header has no %q column", required)
+			}
+		}
+
+		return &csvReader{r: cr, index: index, line: 1}, nil
+	case FormatNDJSON:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+		return &ndjsonReader{s: scanner}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+}
+
+type csvReader struct {
+	r     *csv.Reader
+	index map[string]int
+	line  int
+}
+
+func (c *csvReader) Read() (models.People, error) {
+	row, err := c.r.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			c.line = parseErr.StartLine
+
+			return models.People{}, fmt.Errorf("%w: %w", ErrInvalidRecord, err)
+		}
+
+		return models.People{}, err
+	}
+
+	c.line, _ = c.r.FieldPos(0)
+
+	get := func(column string) string {
+		if i, ok := c.index[column]; ok && i < len(row) {
+			return row[i]
+		}
+
+		return ""
+	}
+
+	var r record
+
+	r.Name = get("name")
+	r.Surname = get("surname")
+	r.Patronym = get("patronym")
+	r.Gender = get("gender")
+	r.Nationality = get("nationality")
+	r.EnrichmentStatus = get("enrichment_status")
+
+	if v := get("id"); v != "" {
+		if r.ID, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return models.People{}, fmt.Errorf("%w: id is not a number", ErrInvalidRecord)
+		}
+	}
+
+	if v := get("age"); v != "" {
+		if r.Age, err = strconv.Atoi(v); err != nil {
+			return models.People{}, fmt.Errorf("%w: age is not a number", ErrInvalidRecord)
+		}
+	}
+
+	if v := get("created_at"); v != "" {
+		if r.CreatedAt, err = time.Parse(time.RFC3339Nano, v); err != nil {
+			return models.People{}, fmt.Errorf("%w: created_at is not a timestamp", ErrInvalidRecord)
+		}
+	}
+
+	return r.people(), nil
+}
+
+func (c *csvReader) Line() int {
+	return c.line
+}
+
+type ndjsonReader struct {
+	s    *bufio.Scanner
+	line int
+}
+
+func (n *ndjsonReader) Read() (models.People, error) {
+	for n.s.Scan() {
+		n.line++
+
+		line := bytes.TrimSpace(n.s.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var r record
+		if err := json.Unmarshal(line, &r); err != nil {
+			return models.People{}, fmt.Errorf("%w: %w", ErrInvalidRecord, err)
+		}
+
+		return r.people(), nil
+	}
+
+	if err := n.s.Err(); err != nil {
+		return models.People{}, err
+	}
+
+	return models.People{}, io.EOF
+}
+
+func (n *ndjsonReader) Line() int {
+	return n.line
+}
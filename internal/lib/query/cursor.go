@@ -0,0 +1,143 @@
+package query
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"predictor/internal/domain/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+type cursorPayload struct {
+	Sort     string   `json:"s"`
+	Values   []string `json:"v"`
+	Backward bool     `json:"b,omitempty"`
+}
+
+// NewCursor returns a cursor pointing at p in the given sort.
+func NewCursor(p models.People, sort []models.SortKey, backward bool) models.Cursor {
+	keys := models.StableSort(sort)
+
+	values := make([]string, 0, len(keys))
+	for _, k := range keys {
+		values = append(values, sortValue(p, k.Field))
+	}
+
+	return models.Cursor{Values: values, Backward: backward}
+}
+
+// EncodeCursor returns an opaque cursor pointing at p. The cursor is only
+// valid together with the same sort.
+func EncodeCursor(p models.People, sort []models.SortKey, backward bool) string {
+	c := NewCursor(p, sort, backward)
+
+	payload, _ := json.Marshal(cursorPayload{
+		Sort:     sortString(sort),
+		Values:   c.Values,
+		Backward: c.Backward,
+	})
+
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+// DecodeCursor parses a cursor made by EncodeCursor for the same sort.
+func DecodeCursor(v string, sort []models.SortKey) (models.Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(v)
+	if err != nil {
+		return models.Cursor{}, ErrInvalidCursor
+	}
+
+	var payload cursorPayload
+
+	if err = json.Unmarshal(raw, &payload); err != nil {
+		return models.Cursor{}, ErrInvalidCursor
+	}
+
+	if payload.Sort != sortString(sort) {
+		return models.Cursor{}, fmt.Errorf("%w: it was issued for another sort", ErrInvalidCursor)
+	}
+
+	keys := models.StableSort(sort)
+
+	if len(payload.Values) != len(keys) {
+		return models.Cursor{}, ErrInvalidCursor
+	}
+
+	for i, k := range keys {
+		if !validSortValue(k.Field, payload.Values[i]) {
+			return models.Cursor{}, fmt.Errorf("%w: bad %s value", ErrInvalidCursor, k.Field)
+		}
+	}
+
+	return models.Cursor{
+		Values:   payload.Values,
+		Backward: payload.Backward,
+	}, nil
+}
+
+func sortString(sort []models.SortKey) string {
+	parts := make([]string, 0, len(sort))
+	for _, k := range sort {
+		if k.Desc {
+			parts = append(parts, k.Field+":desc")
+		} else {
+			parts = append(parts, k.Field)
+		}
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// validSortValue reports whether v can be a value of the sort field, as
+// sortValue writes it and the storage reads it back.
+func validSortValue(field, v string) bool {
+	var err error
+
+	switch field {
+	case models.SortID:
+		_, err = strconv.ParseInt(v, 10, 64)
+	case models.SortAge:
+		_, err = strconv.ParseInt(v, 10, 32)
+	case models.SortCreatedAt:
+		_, err = time.Parse(time.RFC3339Nano, v)
+	case models.SortScore:
+		_, err = strconv.ParseFloat(v, 32)
+	default:
+		// Postgres text cannot hold NUL.
+		return !strings.ContainsRune(v, 0)
+	}
+
+	return err == nil
+}
+
+func sortValue(p models.People, field string) string {
+	switch field {
+	case models.SortID:
+		return strconv.FormatInt(p.ID, 10)
+	case models.SortName:
+		return p.Name
+	case models.SortSurname:
+		return p.Surname
+	case models.SortAge:
+		return strconv.Itoa(p.Age)
+	case models.SortGender:
+		return p.Gender
+	case models.SortNationality:
+		return p.Nationality
+	case models.SortCreatedAt:
+		return p.CreatedAt.Format(time.RFC3339Nano)
+	case models.SortScore:
+		if p.Score == nil {
+			return "0"
+		}
+
+		return strconv.FormatFloat(*p.Score, 'g', -1, 64)
+	default:
+		return ""
+	}
+}
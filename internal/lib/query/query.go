@@ -0,0 +1,110 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"predictor/internal/domain/models"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// ParsePeopleFilter reads the people filters from URL query parameters:
+//
+//	q                         fuzzy search over the full name
+//	name, surname, patronym   exact value, * as a wildcard, ! prefix to negate
+//	ignore_case               match name fields case-insensitively
+//	gender, nationality       comma-separated values, ! prefix to negate
+//	age, age_min, age_max     exact age or an inclusive range
+//	source, max_probability   provenance of at least one attribute
+func ParsePeopleFilter(q url.Values) (models.PeopleFilter, error) {
+	var f models.PeopleFilter
+	var err error
+
+	ignoreCase := false
+	if v := q.Get("ignore_case"); v != "" {
+		if ignoreCase, err = strconv.ParseBool(v); err != nil {
+			return f, fmt.Errorf("%w: ignore_case must be a boolean", ErrInvalidFilter)
+		}
+	}
+
+	f.Query = strings.TrimSpace(q.Get("q"))
+	f.Name = textFilter(q.Get("name"), ignoreCase)
+	f.Surname = textFilter(q.Get("surname"), ignoreCase)
+	f.Patronym = textFilter(q.Get("patronym"), ignoreCase)
+	f.Gender = setFilter(q.Get("gender"))
+	f.Nationality = setFilter(q.Get("nationality"))
+
+	if f.Age, err = intParam(q, "age"); err != nil {
+		return f, err
+	}
+
+	if f.AgeMin, err = intParam(q, "age_min"); err != nil {
+		return f, err
+	}
+
+	if f.AgeMax, err = intParam(q, "age_max"); err != nil {
+		return f, err
+	}
+
+	if f.AgeMin != 0 && f.AgeMax != 0 && f.AgeMin > f.AgeMax {
+		return f, fmt.Errorf("%w: age_min is greater than age_max", ErrInvalidFilter)
+	}
+
+	switch f.Source = q.Get("source"); f.Source {
+	case "", models.SourcePredicted, models.SourceManual:
+	default:
+		return f, fmt.Errorf("%w: source must be %s or %s", ErrInvalidFilter, models.SourcePredicted, models.SourceManual)
+	}
+
+	if v := q.Get("max_probability"); v != "" {
+		f.MaxProbability, err = strconv.ParseFloat(v, 64)
+		if err != nil || f.MaxProbability < 0 || f.MaxProbability > 1 {
+			return f, fmt.Errorf("%w: max_probability must be a number between 0 and 1", ErrInvalidFilter)
+		}
+	}
+
+	return f, nil
+}
+
+func textFilter(v string, ignoreCase bool) models.TextFilter {
+	negate := strings.HasPrefix(v, "!")
+
+	return models.TextFilter{
+		Pattern:    strings.TrimPrefix(v, "!"),
+		IgnoreCase: ignoreCase,
+		Negate:     negate,
+	}
+}
+
+func setFilter(v string) models.SetFilter {
+	negate := strings.HasPrefix(v, "!")
+
+	var values []string
+	for _, s := range strings.Split(strings.TrimPrefix(v, "!"), ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			values = append(values, s)
+		}
+	}
+
+	return models.SetFilter{
+		Values: values,
+		Negate: negate && len(values) > 0,
+	}
+}
+
+func intParam(q url.Values, key string) (int, error) {
+	v := q.Get(key)
+	if v == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%w: %s must be a non-negative integer", ErrInvalidFilter, key)
+	}
+
+	return n, nil
+}
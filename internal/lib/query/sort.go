@@ -0,0 +1,85 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"predictor/internal/domain/models"
+	"strings"
+)
+
+var ErrInvalidSort = errors.New("invalid sort")
+
+var sortFields = map[string]bool{
+	models.SortID:          true,
+	models.SortName:        true,
+	models.SortSurname:     true,
+	models.SortAge:         true,
+	models.SortGender:      true,
+	models.SortNationality: true,
+	models.SortCreatedAt:   true,
+	models.SortScore:       true,
+}
+
+// ParseSort reads a comma-separated list of sort keys. A key is a field
+// name, optionally followed by :asc or :desc, or prefixed with - for
+// descending order, e.g. "age:desc,name" or "-age,name".
+func ParseSort(v string) ([]models.SortKey, error) {
+	if v == "" {
+		return nil, nil
+	}
+
+	var keys []models.SortKey
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(v, ",") {
+		part = strings.TrimSpace(part)
+
+		var key models.SortKey
+
+		field, dir, hasDir := strings.Cut(part, ":")
+		switch {
+		case strings.HasPrefix(field, "-") && !hasDir:
+			key = models.SortKey{Field: field[1:], Desc: true}
+		case !hasDir || dir == "asc":
+			key = models.SortKey{Field: field}
+		case dir == "desc":
+			key = models.SortKey{Field: field, Desc: true}
+		default:
+			return nil, fmt.Errorf("%w: unknown direction %q", ErrInvalidSort, dir)
+		}
+
+		if !sortFields[key.Field] {
+			return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidSort, key.Field)
+		}
+
+		if seen[key.Field] {
+			return nil, fmt.Errorf("%w: field %q is repeated", ErrInvalidSort, key.Field)
+		}
+		seen[key.Field] = true
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// ResolveSort checks the sort against the filter and fills in the default:
+// search results go best match first unless asked otherwise. Sorting by
+// score needs a search query.
+func ResolveSort(sort []models.SortKey, filter models.PeopleFilter) ([]models.SortKey, error) {
+	if filter.Query == "" {
+		for _, k := range sort {
+			if k.Field == models.SortScore {
+				return nil, fmt.Errorf("%w: sorting by score requires q", ErrInvalidSort)
+			}
+		}
+
+		return sort, nil
+	}
+
+	if len(sort) == 0 {
+		return []models.SortKey{{Field: models.SortScore, Desc: true}}, nil
+	}
+
+	return sort, nil
+}
@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"predictor/internal/storage"
+	"time"
+)
+
+type cacheKey struct {
+	kind   string
+	name   string
+	locale string
+}
+
+type cacheEntry struct {
+	payload   []byte
+	expiresAt time.Time
+}
+
+func (s *Storage) GetCached(_ context.Context, kind, name, locale string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.cache[cacheKey{kind, name, locale}]
+	if !ok || !e.expiresAt.After(now()) {
+		return nil, storage.ErrCacheMiss
+	}
+
+	return bytes.Clone(e.payload), nil
+}
+
+func (s *Storage) SaveCached(_ context.Context, kind, name, locale string, payload []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cache[cacheKey{kind, name, locale}] = cacheEntry{
+		payload:   bytes.Clone(payload),
+		expiresAt: now().Add(ttl),
+	}
+
+	return nil
+}
+
+// PurgeExpiredCache deletes expired cache entries and returns how many.
+func (s *Storage) PurgeExpiredCache(_ context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := now()
+
+	var purged int64
+
+	for key, e := range s.cache {
+		if !e.expiresAt.After(t) {
+			delete(s.cache, key)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,251 @@
+package memory
+
+import (
+	"context"
+	"predictor/internal/domain/models"
+	"predictor/internal/storage"
+	"strconv"
+	"time"
+)
+
+type job struct {
+	id              int64
+	attempts        int
+	overwriteManual bool
+	lastError       string
+	runAt           time.Time
+	lockedUntil     time.Time
+}
+
+// enqueue schedules enrichment of a person now. A job already waiting for
+// the person is restarted with the new settings.
+func (s *Storage) enqueue(peopleID int64, overwriteManual bool) {
+	j, ok := s.jobs[peopleID]
+	if !ok {
+		s.lastJobID++
+		j = &job{id: s.lastJobID}
+		s.jobs[peopleID] = j
+	}
+
+	j.overwriteManual = overwriteManual
+	j.attempts = 0
+	j.lastError = ""
+	j.runAt = now()
+	j.lockedUntil = time.Time{}
+}
+
+// job returns the job with the given id if it still exists.
+func (s *Storage) job(j models.EnrichmentJob) (*job, bool) {
+	found, ok := s.jobs[j.PeopleID]
+	if !ok || found.id != j.ID {
+		return nil, false
+	}
+
+	return found, true
+}
+
+// ClaimEnrichmentJob locks the oldest due job for the lease period. Jobs
+// whose lease ran out are claimed again.
+func (s *Storage) ClaimEnrichmentJob(_ context.Context, lease time.Duration) (models.EnrichmentJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := now()
+
+	var claimed *job
+	var peopleID int64
+
+	for id, j := range s.jobs {
+		if j.runAt.After(t) || j.lockedUntil.After(t) {
+			continue
+		}
+
+		if claimed == nil || j.runAt.Before(claimed.runAt) || j.runAt.Equal(claimed.runAt) && j.id < claimed.id {
+			claimed, peopleID = j, id
+		}
+	}
+
+	if claimed == nil {
+		return models.EnrichmentJob{}, storage.ErrNoJobs
+	}
+
+	claimed.attempts++
+	claimed.lockedUntil = t.Add(lease)
+
+	return models.EnrichmentJob{
+		ID:              claimed.id,
+		PeopleID:        peopleID,
+		Name:            s.people[peopleID].Name,
+		Attempts:        claimed.attempts,
+		OverwriteManual: claimed.overwriteManual,
+	}, nil
+}
+
+// CompleteEnrichment applies the prediction and removes the job.
+func (s *Storage) CompleteEnrichment(_ context.Context, j models.EnrichmentJob, prediction models.Prediction) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.applyEnrichment(j.PeopleID, prediction, j.OverwriteManual); err != nil {
+		return err
+	}
+
+	if _, ok := s.job(j); ok {
+		delete(s.jobs, j.PeopleID)
+	}
+
+	return nil
+}
+
+// RetryEnrichmentJob releases the job and schedules it after delay.
+func (s *Storage) RetryEnrichmentJob(_ context.Context, j models.EnrichmentJob, delay time.Duration, reason string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if found, ok := s.job(j); ok {
+		found.lockedUntil = time.Time{}
+		found.lastError = reason
+		found.runAt = now().Add(delay)
+	}
+
+	return nil
+}
+
+// FailEnrichment gives up on the job and marks the person as failed.
+func (s *Storage) FailEnrichment(_ context.Context, j models.EnrichmentJob) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if p, ok := s.people[j.PeopleID]; ok {
+		p.EnrichmentStatus = models.EnrichmentFailed
+	}
+
+	if _, ok := s.job(j); ok {
+		delete(s.jobs, j.PeopleID)
+	}
+
+	return nil
+}
+
+// SavePrediction replaces the stored prediction of a person.
+func (s *Storage) SavePrediction(_ context.Context, id int64, prediction models.Prediction) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.people[id]; !ok {
+		return storage.ErrPeopleNotFound
+	}
+
+	s.predictions[id] = prediction
+
+	return nil
+}
+
+// EnqueueEnrichment schedules re-enrichment of every person matching the
+// filter and returns how many were queued.
+func (s *Storage) EnqueueEnrichment(_ context.Context, overwriteManual bool, filter models.PeopleFilter) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m := newMatcher(filter)
+
+	var queued int64
+
+	for id, p := range s.people {
+		if _, ok := m.match(p); !ok {
+			continue
+		}
+
+		s.enqueue(id, overwriteManual)
+		p.EnrichmentStatus = models.EnrichmentPending
+
+		queued++
+	}
+
+	return queued, nil
+}
+
+// ApplyEnrichment writes the predicted attributes of a person, reports every
+// value that changed and stores the full prediction. Attributes that were set
+// manually are left alone unless overwriteManual is true.
+func (s *Storage) ApplyEnrichment(_ context.Context, id int64, prediction models.Prediction, overwriteManual bool) (models.EnrichmentResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.applyEnrichment(id, prediction, overwriteManual)
+}
+
+func (s *Storage) applyEnrichment(id int64, prediction models.Prediction, overwriteManual bool) (models.EnrichmentResult, error) {
+	p, ok := s.people[id]
+	if !ok {
+		return models.EnrichmentResult{}, storage.ErrPeopleNotFound
+	}
+
+	current := map[string]string{
+		models.FieldAge:         "",
+		models.FieldGender:      p.Gender,
+		models.FieldNationality: p.Nationality,
+	}
+	if p.Age != 0 {
+		current[models.FieldAge] = strconv.Itoa(p.Age)
+	}
+
+	best := prediction.Nationality.Best()
+
+	predicted := map[string]string{
+		models.FieldAge:         strconv.Itoa(prediction.Age.Age),
+		models.FieldGender:      prediction.Gender.Gender,
+		models.FieldNationality: best.CountryID,
+	}
+
+	provenance := map[string]models.Provenance{
+		models.FieldAge: {
+			Source:   models.SourcePredicted,
+			Provider: prediction.Age.Provider,
+		},
+		models.FieldGender: {
+			Source:      models.SourcePredicted,
+			Provider:    prediction.Gender.Provider,
+			Probability: &prediction.Gender.Probability,
+		},
+		models.FieldNationality: {
+			Source:      models.SourcePredicted,
+			Provider:    prediction.Nationality.Provider,
+			Probability: &best.Probability,
+		},
+	}
+
+	var result models.EnrichmentResult
+
+	for _, field := range []string{models.FieldAge, models.FieldGender, models.FieldNationality} {
+		if p.Provenance[field].Source == models.SourceManual && !overwriteManual {
+			result.Skipped = append(result.Skipped, field)
+
+			continue
+		}
+
+		if current[field] != predicted[field] {
+			switch field {
+			case models.FieldAge:
+				p.Age = prediction.Age.Age
+			case models.FieldGender:
+				p.Gender = prediction.Gender.Gender
+			case models.FieldNationality:
+				p.Nationality = best.CountryID
+			}
+
+			result.Changes = append(result.Changes, models.AttributeChange{
+				Field: field,
+				Old:   current[field],
+				New:   predicted[field],
+			})
+		}
+
+		setProvenance(p, field, provenance[field])
+	}
+
+	s.predictions[id] = prediction
+	p.EnrichmentStatus = models.EnrichmentComplete
+
+	return result, nil
+}
@@ -0,0 +1,525 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"predictor/internal/domain/models"
+	"predictor/internal/storage"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Storage keeps people, enrichment jobs and the enrichment cache in process
+// memory. It has the methods of postgres.Storage with the same semantics and
+// errors, so the service can run without a database, e.g. for demos. Nothing
+// survives a restart.
+type Storage struct {
+	mu sync.Mutex
+
+	people      map[int64]*models.People
+	lastID      int64
+	predictions map[int64]models.Prediction
+	// jobs are keyed by person, as a person has at most one job.
+	jobs      map[int64]*job
+	lastJobID int64
+	cache     map[cacheKey]cacheEntry
+}
+
+func New() *Storage {
+	return &Storage{
+		people:      make(map[int64]*models.People),
+		predictions: make(map[int64]models.Prediction),
+		jobs:        make(map[int64]*job),
+		cache:       make(map[cacheKey]cacheEntry),
+	}
+}
+
+// Close is a no-op, there is nothing to release.
+func (s *Storage) Close() {}
+
+// now returns the current time at the precision Postgres stores.
+func now() time.Time {
+	return time.Now().Truncate(time.Microsecond)
+}
+
+// SavePeople stores a person in the pending state together with the job
+// that will enrich them, and returns the stored person.
+func (s *Storage) SavePeople(_ context.Context, name, surname, patronym string) (models.People, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.insert(models.People{Name: name, Surname: surname, Patronymic: patronym}, true)
+
+	return clone(s.people[id]), nil
+}
+
+// SavePeopleBatch stores people like SavePeople. IDs are returned in input
+// order.
+func (s *Storage) SavePeopleBatch(_ context.Context, people []models.People) ([]int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]int64, 0, len(people))
+
+	for _, p := range people {
+		ids = append(ids, s.insert(models.People{Name: p.Name, Surname: p.Surname, Patronymic: p.Patronymic}, true))
+	}
+
+	return ids, nil
+}
+
+// ImportPeople stores people together with whatever attributes they already
+// have. With enrich, people missing an attribute are left pending and queued
+// for enrichment; the others are complete. IDs are returned in input order.
+func (s *Storage) ImportPeople(_ context.Context, people []models.People, enrich bool) ([]int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]int64, 0, len(people))
+
+	for _, p := range people {
+		ids = append(ids, s.insert(models.People{
+			Name:        p.Name,
+			Surname:     p.Surname,
+			Patronymic:  p.Patronymic,
+			Age:         p.Age,
+			Gender:      p.Gender,
+			Nationality: p.Nationality,
+		}, enrich && (p.Age == 0 || p.Gender == "" || p.Nationality == "")))
+	}
+
+	return ids, nil
+}
+
+// insert adds a person, pending with a job when enrich is set and complete
+// otherwise.
+func (s *Storage) insert(p models.People, enrich bool) int64 {
+	s.lastID++
+
+	p.ID = s.lastID
+	p.CreatedAt = now()
+	p.EnrichmentStatus = models.EnrichmentComplete
+
+	if enrich {
+		p.EnrichmentStatus = models.EnrichmentPending
+		s.enqueue(p.ID, false)
+	}
+
+	s.people[p.ID] = &p
+
+	return p.ID
+}
+
+func (s *Storage) DeletePeople(_ context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.people[id]; !ok {
+		return storage.ErrPeopleNotFound
+	}
+
+	delete(s.people, id)
+	delete(s.predictions, id)
+	delete(s.jobs, id)
+
+	return nil
+}
+
+// UpdatePeople changes the given attributes and marks them as set manually.
+func (s *Storage) UpdatePeople(_ context.Context, name, surname, patronym, gender, nationality string, age int, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.people[id]
+	if !ok {
+		return storage.ErrPeopleNotFound
+	}
+
+	if name != "" {
+		p.Name = name
+	}
+
+	if surname != "" {
+		p.Surname = surname
+	}
+
+	if patronym != "" {
+		p.Patronymic = patronym
+	}
+
+	if age != 0 {
+		p.Age = age
+		setProvenance(p, models.FieldAge, models.Provenance{Source: models.SourceManual})
+	}
+
+	if gender != "" {
+		p.Gender = gender
+		setProvenance(p, models.FieldGender, models.Provenance{Source: models.SourceManual})
+	}
+
+	if nationality != "" {
+		p.Nationality = nationality
+		setProvenance(p, models.FieldNationality, models.Provenance{Source: models.SourceManual})
+	}
+
+	return nil
+}
+
+func (s *Storage) GetPeopleByID(_ context.Context, id int64) (models.People, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.people[id]
+	if !ok {
+		return models.People{}, storage.ErrPeopleNotFound
+	}
+
+	return clone(p), nil
+}
+
+func (s *Storage) GetPeople(_ context.Context, limit, offset int64, filter models.PeopleFilter, sort []models.SortKey) ([]models.People, int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	people := s.find(filter)
+	slices.SortFunc(people, comparePeople(sort, false))
+
+	total := int64(len(people))
+
+	lo := min(max(offset, 0), total)
+	hi := min(lo+max(limit, 0), total)
+
+	if lo == hi {
+		return nil, total, nil
+	}
+
+	return people[lo:hi], total, nil
+}
+
+// GetPeopleAfter returns up to limit people following the cursor in sort
+// order, or preceding it for a backward cursor. A cursor without values
+// starts from the beginning. more reports whether rows remain beyond the
+// returned ones in the direction of travel.
+func (s *Storage) GetPeopleAfter(_ context.Context, limit int64, filter models.PeopleFilter, sort []models.SortKey, cursor models.Cursor) ([]models.People, bool, error) {
+	const op = "storage.memory.GetPeopleAfter"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	people := s.find(filter)
+	compare := comparePeople(sort, cursor.Backward)
+
+	if len(cursor.Values) > 0 {
+		at, err := cursorPeople(sort, cursor)
+		if err != nil {
+			return nil, false, fmt.Errorf("%s: %w", op, err)
+		}
+
+		people = slices.DeleteFunc(people, func(p models.People) bool {
+			return compare(p, at) <= 0
+		})
+	}
+
+	slices.SortFunc(people, compare)
+
+	more := int64(len(people)) > limit
+	if more {
+		people = people[:limit]
+	}
+
+	if len(people) == 0 {
+		return nil, false, nil
+	}
+
+	if cursor.Backward {
+		slices.Reverse(people)
+	}
+
+	return people, more, nil
+}
+
+// StreamPeople calls fn for every person matching the filter, in sort order.
+// The matches are taken at the start, so fn may use the storage. An error
+// from fn stops the stream and is returned as is.
+func (s *Storage) StreamPeople(ctx context.Context, filter models.PeopleFilter, sort []models.SortKey, fn func(models.People) error) error {
+	const op = "storage.memory.StreamPeople"
+
+	s.mu.Lock()
+	people := s.find(filter)
+	s.mu.Unlock()
+
+	slices.SortFunc(people, comparePeople(sort, false))
+
+	for _, p := range people {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// find returns copies of the people matching the filter, scored when it has
+// a search query.
+func (s *Storage) find(f models.PeopleFilter) []models.People {
+	m := newMatcher(f)
+
+	var people []models.People
+
+	for _, p := range s.people {
+		score, ok := m.match(p)
+		if !ok {
+			continue
+		}
+
+		c := clone(p)
+		c.Score = score
+
+		people = append(people, c)
+	}
+
+	return people
+}
+
+func clone(p *models.People) models.People {
+	c := *p
+	c.Provenance = maps.Clone(p.Provenance)
+
+	return c
+}
+
+func setProvenance(p *models.People, field string, provenance models.Provenance) {
+	if p.Provenance == nil {
+		p.Provenance = make(map[string]models.Provenance)
+	}
+
+	provenance.UpdatedAt = now()
+	p.Provenance[field] = provenance
+}
+
+// matcher is a PeopleFilter prepared for matching many people.
+type matcher struct {
+	f                       models.PeopleFilter
+	name, surname, patronym func(string) bool
+}
+
+func newMatcher(f models.PeopleFilter) matcher {
+	return matcher{
+		f:        f,
+		name:     textMatcher(f.Name),
+		surname:  textMatcher(f.Surname),
+		patronym: textMatcher(f.Patronym),
+	}
+}
+
+// match reports whether p passes the filter and, for a search, its score.
+// Unknown attributes are stored as zero values and, like NULL in SQL, match
+// no condition on them except a negated set.
+func (m matcher) match(p *models.People) (*float64, bool) {
+	f := m.f
+
+	var score *float64
+
+	if f.Query != "" {
+		found, similarity := search(f.Query, p.Name+" "+p.Surname+" "+p.Patronymic)
+		if !found {
+			return nil, false
+		}
+
+		score = &similarity
+	}
+
+	if !m.name(p.Name) || !m.surname(p.Surname) || !m.patronym(p.Patronymic) {
+		return nil, false
+	}
+
+	if f.Age != 0 && (p.Age == 0 || p.Age != f.Age) {
+		return nil, false
+	}
+
+	if f.AgeMin != 0 && (p.Age == 0 || p.Age < f.AgeMin) {
+		return nil, false
+	}
+
+	if f.AgeMax != 0 && (p.Age == 0 || p.Age > f.AgeMax) {
+		return nil, false
+	}
+
+	if !matchSet(p.Gender, f.Gender) || !matchSet(p.Nationality, f.Nationality) {
+		return nil, false
+	}
+
+	if f.Source != "" || f.MaxProbability > 0 {
+		found := false
+
+		for _, provenance := range p.Provenance {
+			if f.Source != "" && provenance.Source != f.Source {
+				continue
+			}
+
+			if f.MaxProbability > 0 && (provenance.Probability == nil || *provenance.Probability > f.MaxProbability) {
+				continue
+			}
+
+			found = true
+		}
+
+		if !found {
+			return nil, false
+		}
+	}
+
+	return score, true
+}
+
+// textMatcher matches like LIKE and ILIKE do for patterns with * wildcards,
+// and compares whole values otherwise.
+func textMatcher(t models.TextFilter) func(string) bool {
+	if t.Pattern == "" {
+		return func(string) bool { return true }
+	}
+
+	var match func(string) bool
+
+	switch {
+	case strings.Contains(t.Pattern, "*"):
+		parts := strings.Split(t.Pattern, "*")
+		for i, part := range parts {
+			parts[i] = regexp.QuoteMeta(part)
+		}
+
+		expr := "(?s)^" + strings.Join(parts, ".*") + "$"
+		if t.IgnoreCase {
+			expr = "(?i)" + expr
+		}
+
+		match = regexp.MustCompile(expr).MatchString
+	case t.IgnoreCase:
+		pattern := strings.ToLower(t.Pattern)
+		match = func(v string) bool { return strings.ToLower(v) == pattern }
+	default:
+		match = func(v string) bool { return v == t.Pattern }
+	}
+
+	return func(v string) bool { return match(v) != t.Negate }
+}
+
+func matchSet(value string, sf models.SetFilter) bool {
+	if len(sf.Values) == 0 {
+		return true
+	}
+
+	in := value != "" && slices.Contains(sf.Values, value)
+
+	return in != sf.Negate
+}
+
+// comparePeople orders people like the ORDER BY of the postgres storage:
+// by the sort keys, then by id. reverse flips every direction. Text compares
+// bytewise, as in the C collation.
+func comparePeople(sort []models.SortKey, reverse bool) func(a, b models.People) int {
+	keys := models.StableSort(sort)
+
+	return func(a, b models.People) int {
+		for _, k := range keys {
+			c := compareField(a, b, k.Field)
+			if k.Desc != reverse {
+				c = -c
+			}
+
+			if c != 0 {
+				return c
+			}
+		}
+
+		return 0
+	}
+}
+
+func compareField(a, b models.People, field string) int {
+	switch field {
+	case models.SortID:
+		return cmp.Compare(a.ID, b.ID)
+	case models.SortName:
+		return strings.Compare(a.Name, b.Name)
+	case models.SortSurname:
+		return strings.Compare(a.Surname, b.Surname)
+	case models.SortAge:
+		return cmp.Compare(a.Age, b.Age)
+	case models.SortGender:
+		return strings.Compare(a.Gender, b.Gender)
+	case models.SortNationality:
+		return strings.Compare(a.Nationality, b.Nationality)
+	case models.SortCreatedAt:
+		return a.CreatedAt.Compare(b.CreatedAt)
+	case models.SortScore:
+		return cmp.Compare(scoreOf(a), scoreOf(b))
+	default:
+		return 0
+	}
+}
+
+func scoreOf(p models.People) float64 {
+	if p.Score == nil {
+		return 0
+	}
+
+	return *p.Score
+}
+
+// cursorPeople turns the cursor values back into a person that rows can be
+// compared with.
+func cursorPeople(sort []models.SortKey, cursor models.Cursor) (models.People, error) {
+	keys := models.StableSort(sort)
+
+	if len(cursor.Values) != len(keys) {
+		return models.People{}, errors.New("cursor does not match the sort")
+	}
+
+	var p models.People
+
+	for i, k := range keys {
+		v := cursor.Values[i]
+
+		var err error
+
+		switch k.Field {
+		case models.SortID:
+			p.ID, err = strconv.ParseInt(v, 10, 64)
+		case models.SortName:
+			p.Name = v
+		case models.SortSurname:
+			p.Surname = v
+		case models.SortAge:
+			p.Age, err = strconv.Atoi(v)
+		case models.SortGender:
+			p.Gender = v
+		case models.SortNationality:
+			p.Nationality = v
+		case models.SortCreatedAt:
+			p.CreatedAt, err = time.Parse(time.RFC3339Nano, v)
+		case models.SortScore:
+			var score float64
+			// Scores are single precision, like the real they are in SQL.
+			score, err = strconv.ParseFloat(v, 32)
+			p.Score = &score
+		}
+
+		if err != nil {
+			return models.People{}, fmt.Errorf("cursor value %q for %s: %w", v, k.Field, err)
+		}
+	}
+
+	return p, nil
+}
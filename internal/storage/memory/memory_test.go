@@ -0,0 +1,13 @@
+package memory_test
+
+import (
+	"predictor/internal/storage/memory"
+	"predictor/internal/storage/storagetest"
+	"testing"
+)
+
+func TestStorage(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storagetest.Storage {
+		return memory.New()
+	})
+}
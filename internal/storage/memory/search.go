@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// similarityThreshold is the default pg_trgm.word_similarity_threshold used
+// by the <% operator.
+const similarityThreshold = 0.6
+
+// search reproduces the people search of the postgres storage: a person is
+// found when the full name has every word of the query, as the 'simple'
+// full-text configuration sees it, or when the query is similar enough to a
+// part of the full name. The score is pg_trgm's word_similarity, rounded to
+// single precision as it is returned by Postgres.
+func search(query, fullName string) (bool, float64) {
+	score := float64(float32(wordSimilarity(query, fullName)))
+
+	return hasWords(fullName, words(query)) || score >= similarityThreshold, score
+}
+
+// words splits s into lower case words of letters and digits.
+func words(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func hasWords(s string, query []string) bool {
+	if len(query) == 0 {
+		return false
+	}
+
+	have := words(s)
+
+	for _, w := range query {
+		if !slices.Contains(have, w) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// trigrams returns the trigrams of s in order, the way pg_trgm makes them:
+// every word is padded with two spaces in front and one behind.
+func trigrams(s string) []string {
+	var trgs []string
+
+	for _, w := range words(s) {
+		r := []rune("  " + w + " ")
+
+		for i := 0; i+3 <= len(r); i++ {
+			trgs = append(trgs, string(r[i:i+3]))
+		}
+	}
+
+	return trgs
+}
+
+// wordSimilarity is the greatest similarity between the trigrams of query
+// and those of any continuous extent of the ordered trigrams of text.
+func wordSimilarity(query, text string) float64 {
+	want := make(map[string]bool)
+	for _, t := range trigrams(query) {
+		want[t] = true
+	}
+
+	if len(want) == 0 {
+		return 0
+	}
+
+	trgs := trigrams(text)
+
+	best := 0.0
+
+	for i := range trgs {
+		seen := make(map[string]bool)
+		common, unique := 0, 0
+
+		for _, t := range trgs[i:] {
+			if seen[t] {
+				continue
+			}
+
+			seen[t] = true
+			unique++
+
+			if want[t] {
+				common++
+			}
+
+			best = max(best, float64(common)/float64(len(want)+unique-common))
+		}
+	}
+
+	return best
+}
@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"predictor/internal/storage"
+	"time"
+)
+
+func (s *Storage) GetCached(ctx context.Context, kind, name, locale string) ([]byte, error) {
+	const op = "storage.postgres.GetCached"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var payload []byte
+
+	err := s.q.QueryRow(ctx, s.stmt(stmtGetCached), kind, name, locale).Scan(&payload)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, storage.ErrCacheMiss
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return payload, nil
+}
+
+func (s *Storage) SaveCached(ctx context.Context, kind, name, locale string, payload []byte, ttl time.Duration) error {
+	const op = "storage.postgres.SaveCached"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	if _, err := s.q.Exec(ctx, s.stmt(stmtSaveCached), kind, name, locale, payload, ttl.Seconds()); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// PurgeExpiredCache deletes expired cache entries and returns how many.
+func (s *Storage) PurgeExpiredCache(ctx context.Context) (int64, error) {
+	const op = "storage.postgres.PurgeExpiredCache"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	purged, err := s.q.Exec(ctx, "DELETE FROM enrichment_cache WHERE expires_at <= now()")
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,388 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"predictor/internal/domain/models"
+	"predictor/internal/storage"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ClaimEnrichmentJob locks the oldest due job for the lease period. Jobs
+// whose lease ran out, e.g. after a crash, are claimed again.
+func (s *Storage) ClaimEnrichmentJob(ctx context.Context, lease time.Duration) (models.EnrichmentJob, error) {
+	const op = "storage.postgres.ClaimEnrichmentJob"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var job models.EnrichmentJob
+
+	err := s.q.QueryRow(ctx, s.stmt(stmtClaimEnrichmentJob), lease.Seconds()).Scan(&job.ID, &job.PeopleID, &job.Attempts, &job.OverwriteManual, &job.Name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.EnrichmentJob{}, storage.ErrNoJobs
+	}
+	if err != nil {
+		return models.EnrichmentJob{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return job, nil
+}
+
+// CompleteEnrichment applies the prediction and removes the job in one
+// transaction, so a job is never lost with its result unsaved.
+func (s *Storage) CompleteEnrichment(ctx context.Context, job models.EnrichmentJob, prediction models.Prediction) error {
+	const op = "storage.postgres.CompleteEnrichment"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	return s.WithTx(ctx, func(tx *Storage) error {
+		if _, err := tx.ApplyEnrichment(ctx, job.PeopleID, prediction, job.OverwriteManual); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if _, err := tx.q.Exec(ctx, tx.stmt(stmtDeleteEnrichmentJob), job.ID); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	})
+}
+
+// RetryEnrichmentJob releases the job and schedules it after delay.
+func (s *Storage) RetryEnrichmentJob(ctx context.Context, job models.EnrichmentJob, delay time.Duration, reason string) error {
+	const op = "storage.postgres.RetryEnrichmentJob"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	if _, err := s.q.Exec(ctx, `
+		UPDATE enrichment_job
+		SET locked_until = NULL, last_error = $1, run_at = now() + make_interval(secs => $2)
+		WHERE id = $3
+	`, reason, delay.Seconds(), job.ID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// FailEnrichment gives up on the job and marks the person as failed.
+func (s *Storage) FailEnrichment(ctx context.Context, job models.EnrichmentJob) error {
+	const op = "storage.postgres.FailEnrichment"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	return s.WithTx(ctx, func(tx *Storage) error {
+		if _, err := tx.q.Exec(ctx,
+			"UPDATE people_info SET enrichment_status = 'failed' WHERE id = $1",
+			job.PeopleID,
+		); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if _, err := tx.q.Exec(ctx, tx.stmt(stmtDeleteEnrichmentJob), job.ID); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	})
+}
+
+// SavePrediction replaces the stored prediction of a person in one
+// transaction.
+func (s *Storage) SavePrediction(ctx context.Context, id int64, prediction models.Prediction) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	return s.WithTx(ctx, func(tx *Storage) error {
+		return tx.savePrediction(ctx, id, prediction)
+	})
+}
+
+func (s *Storage) savePrediction(ctx context.Context, id int64, prediction models.Prediction) error {
+	const op = "storage.postgres.SavePrediction"
+
+	_, err := s.q.Exec(ctx, `
+		INSERT INTO people_prediction(people_id, age, age_count, gender_name, gender_probability, gender_count, nationality_count)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (people_id) DO UPDATE SET
+			age = EXCLUDED.age,
+			age_count = EXCLUDED.age_count,
+			gender_name = EXCLUDED.gender_name,
+			gender_probability = EXCLUDED.gender_probability,
+			gender_count = EXCLUDED.gender_count,
+			nationality_count = EXCLUDED.nationality_count,
+			predicted_at = now()
+	`,
+		id,
+		prediction.Age.Age,
+		prediction.Age.Count,
+		prediction.Gender.Gender,
+		prediction.Gender.Probability,
+		prediction.Gender.Count,
+		prediction.Nationality.Count,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err = s.q.Exec(ctx, "DELETE FROM nationality_prediction WHERE people_id = $1", id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, c := range prediction.Nationality.Countries {
+		if _, err = s.q.Exec(ctx, `
+			INSERT INTO nationality_prediction(people_id, nationality_name, probability)
+			VALUES ($1, $2, $3)
+		`, id, c.CountryID, c.Probability); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return nil
+}
+
+// EnqueueEnrichment schedules re-enrichment of every person matching the
+// filter and returns how many were queued. A job already waiting for a
+// person is restarted with the new settings.
+func (s *Storage) EnqueueEnrichment(ctx context.Context, overwriteManual bool, filter models.PeopleFilter) (int64, error) {
+	const op = "storage.postgres.EnqueueEnrichment"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	cond, args := peopleConditions(filter)
+
+	query := fmt.Sprintf("SELECT id, $%d::boolean FROM people_info", len(args)+1)
+	args = append(args, overwriteManual)
+
+	if len(cond) > 0 {
+		query += " WHERE " + strings.Join(cond, " AND ")
+	}
+
+	queued, err := s.q.Exec(ctx, fmt.Sprintf(`
+		WITH queued AS (
+			INSERT INTO enrichment_job(people_id, overwrite_manual)
+			%s
+			ON CONFLICT (people_id) DO UPDATE SET
+				overwrite_manual = EXCLUDED.overwrite_manual,
+				attempts = 0,
+				last_error = NULL,
+				run_at = now(),
+				locked_until = NULL
+			RETURNING people_id
+		)
+		UPDATE people_info SET enrichment_status = 'pending'
+		FROM queued WHERE people_info.id = queued.people_id
+	`, query), args...)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return queued, nil
+}
+
+// ApplyEnrichment writes the predicted attributes of a person, records every
+// value that changed and stores the full prediction. Attributes that were set
+// manually are left alone unless overwriteManual is true. It all happens in
+// one transaction.
+func (s *Storage) ApplyEnrichment(ctx context.Context, id int64, prediction models.Prediction, overwriteManual bool) (models.EnrichmentResult, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var result models.EnrichmentResult
+
+	err := s.WithTx(ctx, func(tx *Storage) error {
+		var err error
+
+		result, err = tx.applyEnrichment(ctx, id, prediction, overwriteManual)
+
+		return err
+	})
+
+	return result, err
+}
+
+func (s *Storage) applyEnrichment(ctx context.Context, id int64, prediction models.Prediction, overwriteManual bool) (models.EnrichmentResult, error) {
+	const op = "storage.postgres.ApplyEnrichment"
+
+	var age sql.NullInt64
+	var gender, nationality sql.NullString
+
+	err := s.q.QueryRow(ctx, `
+		SELECT age, gender_name, nationality_name
+		FROM people_info LEFT JOIN gender ON gender_id = gender.id LEFT JOIN nationality ON nationality_id = nationality.id
+		WHERE people_info.id = $1
+	`, id).Scan(&age, &gender, &nationality)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.EnrichmentResult{}, storage.ErrPeopleNotFound
+	}
+	if err != nil {
+		return models.EnrichmentResult{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	manual, err := s.manualFields(ctx, id)
+	if err != nil {
+		return models.EnrichmentResult{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	current := map[string]string{
+		models.FieldAge:         "",
+		models.FieldGender:      gender.String,
+		models.FieldNationality: nationality.String,
+	}
+	if age.Valid {
+		current[models.FieldAge] = strconv.FormatInt(age.Int64, 10)
+	}
+
+	best := prediction.Nationality.Best()
+
+	predicted := map[string]string{
+		models.FieldAge:         strconv.Itoa(prediction.Age.Age),
+		models.FieldGender:      prediction.Gender.Gender,
+		models.FieldNationality: best.CountryID,
+	}
+
+	provenance := map[string]models.Provenance{
+		models.FieldAge: {
+			Source:   models.SourcePredicted,
+			Provider: prediction.Age.Provider,
+		},
+		models.FieldGender: {
+			Source:      models.SourcePredicted,
+			Provider:    prediction.Gender.Provider,
+			Probability: &prediction.Gender.Probability,
+		},
+		models.FieldNationality: {
+			Source:      models.SourcePredicted,
+			Provider:    prediction.Nationality.Provider,
+			Probability: &best.Probability,
+		},
+	}
+
+	var result models.EnrichmentResult
+
+	for _, field := range []string{models.FieldAge, models.FieldGender, models.FieldNationality} {
+		if manual[field] && !overwriteManual {
+			result.Skipped = append(result.Skipped, field)
+
+			continue
+		}
+
+		if current[field] != predicted[field] {
+			if err = s.setAttribute(ctx, id, field, predicted[field]); err != nil {
+				return models.EnrichmentResult{}, fmt.Errorf("%s: %w", op, err)
+			}
+
+			if _, err = s.q.Exec(ctx, `
+				INSERT INTO enrichment_change(people_id, field, old_value, new_value)
+				VALUES ($1, $2, NULLIF($3, ''), $4)
+			`, id, field, current[field], predicted[field]); err != nil {
+				return models.EnrichmentResult{}, fmt.Errorf("%s: %w", op, err)
+			}
+
+			result.Changes = append(result.Changes, models.AttributeChange{
+				Field: field,
+				Old:   current[field],
+				New:   predicted[field],
+			})
+		}
+
+		if err = s.setProvenance(ctx, id, field, provenance[field]); err != nil {
+			return models.EnrichmentResult{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err = s.SavePrediction(ctx, id, prediction); err != nil {
+		return models.EnrichmentResult{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err = s.q.Exec(ctx,
+		"UPDATE people_info SET enrichment_status = 'complete' WHERE id = $1",
+		id,
+	); err != nil {
+		return models.EnrichmentResult{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return result, nil
+}
+
+func (s *Storage) setAttribute(ctx context.Context, id int64, field, value string) error {
+	switch field {
+	case models.FieldAge:
+		age, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+
+		_, err = s.q.Exec(ctx, "UPDATE people_info SET age = $1 WHERE id = $2", age, id)
+
+		return err
+	case models.FieldGender:
+		genderId, err := s.SaveGender(ctx, value)
+		if err != nil {
+			return err
+		}
+
+		_, err = s.q.Exec(ctx, "UPDATE people_info SET gender_id = $1 WHERE id = $2", genderId, id)
+
+		return err
+	case models.FieldNationality:
+		nationalityId, err := s.SaveNationality(ctx, value)
+		if err != nil {
+			return err
+		}
+
+		_, err = s.q.Exec(ctx, "UPDATE people_info SET nationality_id = $1 WHERE id = $2", nationalityId, id)
+
+		return err
+	default:
+		return fmt.Errorf("unknown field %q", field)
+	}
+}
+
+func (s *Storage) setProvenance(ctx context.Context, id int64, field string, p models.Provenance) error {
+	_, err := s.q.Exec(ctx, `
+		INSERT INTO attribute_provenance(people_id, field, source, provider, probability)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5)
+		ON CONFLICT (people_id, field) DO UPDATE SET
+			source = EXCLUDED.source,
+			provider = EXCLUDED.provider,
+			probability = EXCLUDED.probability,
+			updated_at = now()
+	`, id, field, p.Source, p.Provider, p.Probability)
+
+	return err
+}
+
+func (s *Storage) manualFields(ctx context.Context, id int64) (map[string]bool, error) {
+	rows, err := s.q.Query(ctx,
+		"SELECT field FROM attribute_provenance WHERE people_id = $1 AND source = $2",
+		id, models.SourceManual,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	manual := make(map[string]bool)
+
+	for rows.Next() {
+		var field string
+
+		if err = rows.Scan(&field); err != nil {
+			return nil, err
+		}
+
+		manual[field] = true
+	}
+
+	return manual, rows.Err()
+}
@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"predictor/internal/config"
+)
+
+// pgxDB is the native pgxpool backend. Every connection prepares the named
+// statements when it opens, a page of people is fetched in one batch with its
+// count and bulk inserts go through COPY.
+type pgxDB struct {
+	pgxConn
+	pool *pgxpool.Pool
+}
+
+// openPgxPool creates the pool. Connections are opened lazily, so an
+// unreachable database only shows on the first ping.
+func openPgxPool(ctx context.Context, cfgStorage config.Storage) (*pgxDB, error) {
+	cfg, err := pgxpool.ParseConfig(DSN(cfgStorage))
+	if err != nil {
+		return nil, err
+	}
+
+	if cfgStorage.MaxOpenConns > 0 {
+		cfg.MaxConns = int32(cfgStorage.MaxOpenConns)
+	}
+	cfg.MaxConnLifetime = cfgStorage.ConnMaxLifetime
+	cfg.MaxConnIdleTime = cfgStorage.ConnMaxIdleTime
+	cfg.AfterConnect = prepareStatements
+
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pgxDB{pgxConn: pgxConn{pool}, pool: pool}, nil
+}
+
+func prepareStatements(ctx context.Context, conn *pgx.Conn) error {
+	for name, query := range statements {
+		if _, err := conn.Prepare(ctx, name, query); err != nil {
+			return fmt.Errorf("prepare %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (d *pgxDB) Begin(ctx context.Context) (transaction, error) {
+	tx, err := d.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pgxTx{pgxConn: pgxConn{tx}, tx: tx}, nil
+}
+
+func (d *pgxDB) Ping(ctx context.Context) error {
+	return d.pool.Ping(ctx)
+}
+
+func (d *pgxDB) Close() {
+	d.pool.Close()
+}
+
+type pgxTx struct {
+	pgxConn
+	tx pgx.Tx
+}
+
+func (t *pgxTx) Commit(ctx context.Context) error {
+	return t.tx.Commit(ctx)
+}
+
+func (t *pgxTx) Rollback(ctx context.Context) error {
+	return t.tx.Rollback(ctx)
+}
+
+// pgxQuerier is what *pgxpool.Pool and pgx.Tx have in common.
+type pgxQuerier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+	CopyFrom(ctx context.Context, table pgx.Identifier, columns []string, src pgx.CopyFromSource) (int64, error)
+}
+
+type pgxConn struct {
+	q pgxQuerier
+}
+
+func (c pgxConn) Exec(ctx context.Context, query string, args ...any) (int64, error) {
+	tag, err := c.q.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func (c pgxConn) Query(ctx context.Context, query string, args ...any) (rows, error) {
+	r, err := c.q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (c pgxConn) QueryRow(ctx context.Context, query string, args ...any) row {
+	return c.q.QueryRow(ctx, query, args...)
+}
+
+func (c pgxConn) CopyFrom(ctx context.Context, table string, columns []string, rows [][]any) (int64, error) {
+	return c.q.CopyFrom(ctx, pgx.Identifier{table}, columns, pgx.CopyFromRows(rows))
+}
+
+// QueryWithCount reads the count before handing out the rows, as results of
+// a batch come back in order.
+func (c pgxConn) QueryWithCount(ctx context.Context, countQuery string, countArgs []any, query string, args []any) (int64, rows, error) {
+	b := &pgx.Batch{}
+	b.Queue(countQuery, countArgs...)
+	b.Queue(query, args...)
+
+	results := c.q.SendBatch(ctx, b)
+
+	var total int64
+
+	if err := results.QueryRow().Scan(&total); err != nil {
+		_ = results.Close()
+
+		return 0, nil, err
+	}
+
+	r, err := results.Query()
+	if err != nil {
+		_ = results.Close()
+
+		return 0, nil, err
+	}
+
+	return total, batchRows{Rows: r, results: results}, nil
+}
+
+// batchRows closes the batch together with its last result.
+type batchRows struct {
+	pgx.Rows
+	results pgx.BatchResults
+}
+
+func (r batchRows) Close() {
+	r.Rows.Close()
+	_ = r.results.Close()
+}
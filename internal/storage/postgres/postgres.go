@@ -1,156 +1,547 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
-	_ "github.com/jackc/pgx/v5/stdlib"
+	"net/url"
 	"predictor/internal/config"
 	"predictor/internal/domain/models"
 	"predictor/internal/storage"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
+)
+
+// Drivers selectable with config.Storage.Driver.
+const (
+	DriverStdlib  = "stdlib"
+	DriverPgxPool = "pgxpool"
 )
 
 type Storage struct {
-	db *sql.DB
+	db database
+	// q runs the queries: db itself, or the transaction inside WithTx.
+	q       querier
+	timeout time.Duration
+	// prepared is set when the backend runs named statements by name.
+	prepared bool
 }
 
-func New(cfgStorage config.Storage) (*Storage, error) {
+// New opens a connection pool with the configured driver and waits until the
+// database answers, retrying with backoff as configured. It gives up early
+// when ctx is done.
+func New(ctx context.Context, cfgStorage config.Storage) (*Storage, error) {
 	const op = "storage.postgres.New"
 
-	db, err := sql.Open(
-		"pgx",
-		fmt.Sprintf("postgres://%s:%s@%s/%s", cfgStorage.User, cfgStorage.Password, cfgStorage.Address, cfgStorage.Name),
-	)
+	if cfgStorage.Address == "" || cfgStorage.User == "" || cfgStorage.Name == "" {
+		return nil, fmt.Errorf("%s: DB_ADDRESS, DB_USER and DB_NAME are required", op)
+	}
+
+	var db database
+	var err error
+
+	switch cfgStorage.Driver {
+	case DriverStdlib, "":
+		db, err = openStdlib(cfgStorage)
+	case DriverPgxPool:
+		db, err = openPgxPool(ctx, cfgStorage)
+	default:
+		err = fmt.Errorf("unknown driver %q", cfgStorage.Driver)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return &Storage{db: db}, nil
+	s := &Storage{db: db, q: db, timeout: cfgStorage.QueryTimeout, prepared: cfgStorage.Driver == DriverPgxPool}
+
+	if err = s.ping(ctx, cfgStorage.ConnectRetries, cfgStorage.ConnectBackoff); err != nil {
+		db.Close()
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return s, nil
 }
 
-func (s *Storage) SaveNationality(nationality string) (int64, error) {
-	const op = "storage.postgres.SaveNationality"
+// Close closes the connection pool.
+func (s *Storage) Close() {
+	s.db.Close()
+}
 
-	var id int64
+// DSN builds the connection URL. Every part is escaped, so credentials may
+// contain any characters.
+func DSN(cfgStorage config.Storage) string {
+	q := url.Values{}
 
-	if err := s.db.QueryRow("SELECT id FROM nationality WHERE nationality_name = $1", nationality).Scan(&id); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			err = s.db.QueryRow(`
-				INSERT INTO nationality (nationality_name)
-				VALUES ($1)
-				RETURNING id
-			`, nationality).Scan(&id)
+	if cfgStorage.SSLMode != "" {
+		q.Set("sslmode", cfgStorage.SSLMode)
+	}
 
-			if err != nil {
-				return 0, fmt.Errorf("%s: %w", op, err)
-			}
+	if cfgStorage.SSLRootCert != "" {
+		q.Set("sslrootcert", cfgStorage.SSLRootCert)
+	}
 
-			return id, nil
+	if cfgStorage.ApplicationName != "" {
+		q.Set("application_name", cfgStorage.ApplicationName)
+	}
+
+	if cfgStorage.StatementTimeout > 0 {
+		q.Set("statement_timeout", strconv.FormatInt(cfgStorage.StatementTimeout.Milliseconds(), 10))
+	}
+
+	// Without a password pgx falls back to the passfile or trust auth.
+	user := url.User(cfgStorage.User)
+	if cfgStorage.Password != "" {
+		user = url.UserPassword(cfgStorage.User, cfgStorage.Password)
+	}
+
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     user,
+		Host:     cfgStorage.Address,
+		Path:     "/" + cfgStorage.Name,
+		RawQuery: q.Encode(),
+	}
+
+	return u.String()
+}
+
+// ping checks the database is reachable, trying retries more times with a
+// doubling backoff.
+func (s *Storage) ping(ctx context.Context, retries int, backoff time.Duration) error {
+	for attempt := 0; ; attempt++ {
+		pingCtx, cancel := s.withTimeout(ctx)
+		err := s.db.Ping(pingCtx)
+		cancel()
+
+		if err == nil {
+			return nil
 		}
 
+		if attempt >= retries {
+			return fmt.Errorf("database is unreachable after %d attempts: %w", attempt+1, err)
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		backoff *= 2
+	}
+}
+
+func (s *Storage) SaveNationality(ctx context.Context, nationality string) (int64, error) {
+	const op = "storage.postgres.SaveNationality"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	id, err := s.dictionaryID(ctx, "nationality", "nationality_name", nationality)
+	if err != nil {
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
 
 	return id, nil
 }
 
-func (s *Storage) SaveGender(gender string) (int64, error) {
+func (s *Storage) SaveGender(ctx context.Context, gender string) (int64, error) {
 	const op = "storage.postgres.SaveGender"
 
-	var id int64
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
 
-	if err := s.db.QueryRow("SELECT id FROM gender WHERE gender_name = $1", gender).Scan(&id); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			err = s.db.QueryRow(`
-				INSERT INTO gender (gender_name)
-				VALUES ($1)
-				RETURNING id
-			`, gender).Scan(&id)
+	id, err := s.dictionaryID(ctx, "gender", "gender_name", gender)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
 
-			if err != nil {
-				return 0, fmt.Errorf("%s: %w", op, err)
-			}
+	return id, nil
+}
 
-			return id, nil
-		}
+// dictionaryID returns the id of value in a dictionary table, inserting it
+// if needed. Concurrent inserts of the same value are safe: the loser of the
+// race gets nothing back from the insert and reads the winner's row.
+func (s *Storage) dictionaryID(ctx context.Context, table, column, value string) (int64, error) {
+	var id int64
 
-		return 0, fmt.Errorf("%s: %w", op, err)
+	err := s.q.QueryRow(ctx, fmt.Sprintf(`
+		INSERT INTO %s (%s)
+		VALUES ($1)
+		ON CONFLICT (%s) DO NOTHING
+		RETURNING id
+	`, table, column, column), value).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = s.q.QueryRow(ctx, fmt.Sprintf("SELECT id FROM %s WHERE %s = $1", table, column), value).Scan(&id)
+	}
+	if err != nil {
+		return 0, err
 	}
 
 	return id, nil
 }
 
-func (s *Storage) GetGender(gender string) (int64, error) {
+func (s *Storage) GetGender(ctx context.Context, gender string) (int64, error) {
 	const op = "storage.postgres.GetGender"
 
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
 	var id int64
 
-	if err := s.db.QueryRow("SELECT id FROM gender WHERE gender_name = $1", gender).Scan(&id); err != nil {
+	if err := s.q.QueryRow(ctx, "SELECT id FROM gender WHERE gender_name = $1", gender).Scan(&id); err != nil {
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
 
 	return id, nil
 }
 
-func (s *Storage) GetNationality(nationality string) (int64, error) {
+func (s *Storage) GetNationality(ctx context.Context, nationality string) (int64, error) {
 	const op = "storage.postgres.GetNationality"
 
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
 	var id int64
 
-	if err := s.db.QueryRow("SELECT id FROM nationality WHERE nationality_name = $1", nationality).Scan(&id); err != nil {
+	if err := s.q.QueryRow(ctx, "SELECT id FROM nationality WHERE nationality_name = $1", nationality).Scan(&id); err != nil {
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
 
 	return id, nil
 }
 
-func (s *Storage) SavePeople(name, surname, patronym, gender, nationality string, age int) (int64, error) {
+// SavePeople stores a person in the pending state together with the job
+// that will enrich them, and returns the stored person.
+func (s *Storage) SavePeople(ctx context.Context, name, surname, patronym string) (models.People, error) {
 	const op = "storage.postgres.SavePeople"
 
-	genderId, err := s.SaveGender(gender)
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	p, err := scanPeople(s.q.QueryRow(ctx, s.stmt(stmtSavePeople), name, surname, patronym))
 	if err != nil {
-		return 0, fmt.Errorf("%s: %w", op, err)
+		return models.People{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return p, nil
+}
+
+// SavePeopleBatch stores people like SavePeople in a single statement, or
+// with COPY where the backend supports it, so either all of them are saved or
+// none. IDs are returned in input order.
+func (s *Storage) SavePeopleBatch(ctx context.Context, people []models.People) ([]int64, error) {
+	const op = "storage.postgres.SavePeopleBatch"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	if _, ok := s.q.(copier); ok {
+		names := make([]models.People, 0, len(people))
+
+		for _, p := range people {
+			names = append(names, models.People{Name: p.Name, Surname: p.Surname, Patronymic: p.Patronymic})
+		}
+
+		ids, err := s.copyPeople(ctx, names, true)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		return ids, nil
+	}
+
+	names := make([]string, 0, len(people))
+	surnames := make([]string, 0, len(people))
+	patronyms := make([]string, 0, len(people))
+
+	for _, p := range people {
+		names = append(names, p.Name)
+		surnames = append(surnames, p.Surname)
+		patronyms = append(patronyms, p.Patronymic)
 	}
 
-	nationalityId, err := s.SaveNationality(nationality)
+	// IDs are taken up front so they can be matched back to the input rows.
+	rows, err := s.q.Query(ctx, `
+		WITH input AS MATERIALIZED (
+			SELECT nextval(pg_get_serial_sequence('people_info', 'id')) AS id, name, surname, patronym, n
+			FROM unnest($1::text[], $2::text[], $3::text[]) WITH ORDINALITY AS t(name, surname, patronym, n)
+		), people AS (
+			INSERT INTO people_info(id, name, surname, patronym, enrichment_status)
+			SELECT id, name, surname, patronym, 'pending' FROM input
+		), jobs AS (
+			INSERT INTO enrichment_job(people_id)
+			SELECT id FROM input
+		)
+		SELECT id FROM input ORDER BY n
+	`, names, surnames, patronyms)
 	if err != nil {
-		return 0, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
+	defer rows.Close()
 
-	var id int64
+	ids := make([]int64, 0, len(people))
 
-	if err = s.db.QueryRow(`
-		INSERT INTO people_info(name, surname, patronym, age, gender_id, nationality_id)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id
-	`, name, surname, patronym, age, genderId, nationalityId).Scan(&id); err != nil {
-		return 0, fmt.Errorf("%s: %w", op, err)
+	for rows.Next() {
+		var id int64
+		if err = rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		ids = append(ids, id)
 	}
 
-	return id, nil
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return ids, nil
 }
 
-func (s *Storage) DeletePeople(id int64) error {
-	const op = "storage.postgres.DeletePeople"
+// ImportPeople stores people together with whatever attributes they already
+// have, all in one statement, or with COPY where the backend supports it.
+// With enrich, people missing an attribute are left pending and queued for
+// enrichment; the others are complete. IDs are returned in input order.
+func (s *Storage) ImportPeople(ctx context.Context, people []models.People, enrich bool) ([]int64, error) {
+	const op = "storage.postgres.ImportPeople"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	if _, ok := s.q.(copier); ok {
+		ids, err := s.copyPeople(ctx, people, enrich)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		return ids, nil
+	}
+
+	var ids []int64
+
+	// Dictionary ids are resolved first, as new values inserted by the
+	// import statement itself would race with concurrent writers.
+	err := s.WithTx(ctx, func(tx *Storage) error {
+		genders := tx.dictionary("gender", "gender_name")
+		nationalities := tx.dictionary("nationality", "nationality_name")
 
-	stmt, err := s.db.Prepare("DELETE FROM people_info WHERE id = $1")
+		var names, surnames, patronyms []string
+		var ages []int
+		var genderIDs, nationalityIDs []*int64
+
+		for _, p := range people {
+			genderID, err := genders.id(ctx, p.Gender)
+			if err != nil {
+				return err
+			}
+
+			nationalityID, err := nationalities.id(ctx, p.Nationality)
+			if err != nil {
+				return err
+			}
+
+			names = append(names, p.Name)
+			surnames = append(surnames, p.Surname)
+			patronyms = append(patronyms, p.Patronymic)
+			ages = append(ages, p.Age)
+			genderIDs = append(genderIDs, genderID)
+			nationalityIDs = append(nationalityIDs, nationalityID)
+		}
+
+		rows, err := tx.q.Query(ctx, `
+			WITH input AS MATERIALIZED (
+				SELECT nextval(pg_get_serial_sequence('people_info', 'id')) AS id, name, surname, patronym,
+					NULLIF(age, 0) AS age, gender_id, nationality_id, n,
+					$7 AND (age = 0 OR gender_id IS NULL OR nationality_id IS NULL) AS enrich
+				FROM unnest($1::text[], $2::text[], $3::text[], $4::int[], $5::bigint[], $6::bigint[])
+					WITH ORDINALITY AS t(name, surname, patronym, age, gender_id, nationality_id, n)
+			), people AS (
+				INSERT INTO people_info(id, name, surname, patronym, age, gender_id, nationality_id, enrichment_status)
+				SELECT id, name, surname, patronym, age, gender_id, nationality_id,
+					CASE WHEN enrich THEN 'pending' ELSE 'complete' END
+				FROM input
+			), jobs AS (
+				INSERT INTO enrichment_job(people_id)
+				SELECT id FROM input WHERE enrich
+			)
+			SELECT id FROM input ORDER BY n
+		`, names, surnames, patronyms, ages, genderIDs, nationalityIDs, enrich)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		ids = make([]int64, 0, len(people))
+
+		for rows.Next() {
+			var id int64
+			if err = rows.Scan(&id); err != nil {
+				return err
+			}
+
+			ids = append(ids, id)
+		}
+
+		return rows.Err()
+	})
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	_, err = stmt.Exec(id)
-	if errors.Is(err, sql.ErrNoRows) {
-		return storage.ErrPeopleNotFound
+	return ids, nil
+}
+
+// dictionary resolves values of a dictionary table to ids, each value once.
+type dictionary struct {
+	s             *Storage
+	table, column string
+	ids           map[string]int64
+}
+
+func (s *Storage) dictionary(table, column string) dictionary {
+	return dictionary{s: s, table: table, column: column, ids: make(map[string]int64)}
+}
+
+// id returns nil, stored as NULL, for a missing value.
+func (d dictionary) id(ctx context.Context, value string) (*int64, error) {
+	if value == "" {
+		return nil, nil
 	}
+
+	id, ok := d.ids[value]
+	if !ok {
+		var err error
+		if id, err = d.s.dictionaryID(ctx, d.table, d.column, value); err != nil {
+			return nil, err
+		}
+
+		d.ids[value] = id
+	}
+
+	return &id, nil
+}
+
+// copyPeople does what ImportPeople does with COPY, in one transaction. COPY
+// returns nothing, so IDs are drawn from the sequence and dictionary ids are
+// looked up beforehand.
+func (s *Storage) copyPeople(ctx context.Context, people []models.People, enrich bool) ([]int64, error) {
+	ids := make([]int64, 0, len(people))
+
+	err := s.WithTx(ctx, func(tx *Storage) error {
+		rows, err := tx.q.Query(ctx,
+			"SELECT nextval(pg_get_serial_sequence('people_info', 'id')) FROM generate_series(1, $1)",
+			len(people),
+		)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var id int64
+			if err = rows.Scan(&id); err != nil {
+				return err
+			}
+
+			ids = append(ids, id)
+		}
+
+		if err = rows.Err(); err != nil {
+			return err
+		}
+
+		genders := tx.dictionary("gender", "gender_name")
+		nationalities := tx.dictionary("nationality", "nationality_name")
+
+		var peopleRows, jobRows [][]any
+
+		for i, p := range people {
+			genderID, err := genders.id(ctx, p.Gender)
+			if err != nil {
+				return err
+			}
+
+			nationalityID, err := nationalities.id(ctx, p.Nationality)
+			if err != nil {
+				return err
+			}
+
+			var age *int
+			if p.Age != 0 {
+				age = &p.Age
+			}
+
+			status := "complete"
+			if enrich && (p.Age == 0 || p.Gender == "" || p.Nationality == "") {
+				status = "pending"
+				jobRows = append(jobRows, []any{ids[i]})
+			}
+
+			peopleRows = append(peopleRows, []any{ids[i], p.Name, p.Surname, p.Patronymic, age, genderID, nationalityID, status})
+		}
+
+		c := tx.q.(copier)
+
+		if _, err = c.CopyFrom(ctx, "people_info",
+			[]string{"id", "name", "surname", "patronym", "age", "gender_id", "nationality_id", "enrichment_status"},
+			peopleRows,
+		); err != nil {
+			return err
+		}
+
+		if len(jobRows) > 0 {
+			if _, err = c.CopyFrom(ctx, "enrichment_job", []string{"people_id"}, jobRows); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (s *Storage) DeletePeople(ctx context.Context, id int64) error {
+	const op = "storage.postgres.DeletePeople"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	deleted, err := s.q.Exec(ctx, s.stmt(stmtDeletePeople), id)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
+	if deleted == 0 {
+		return storage.ErrPeopleNotFound
+	}
+
 	return nil
 }
 
-func (s *Storage) UpdatePeople(name, surname, patronym, gender, nationality string, age int, id int64) error {
+// UpdatePeople changes the given attributes and marks them as set manually,
+// all in one transaction.
+func (s *Storage) UpdatePeople(ctx context.Context, name, surname, patronym, gender, nationality string, age int, id int64) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	return s.WithTx(ctx, func(tx *Storage) error {
+		return tx.updatePeople(ctx, name, surname, patronym, gender, nationality, age, id)
+	})
+}
+
+func (s *Storage) updatePeople(ctx context.Context, name, surname, patronym, gender, nationality string, age int, id int64) error {
 	const op = "storage.postgres.UpdatePeople"
 
 	query := "UPDATE people_info SET"
@@ -167,7 +558,7 @@ func (s *Storage) UpdatePeople(name, surname, patronym, gender, nationality stri
 	}
 
 	if patronym != "" {
-		query += fmt.Sprintf(" patronym = $%d, =", len(args)+1)
+		query += fmt.Sprintf(" patronym = $%d,", len(args)+1)
 		args = append(args, patronym)
 	}
 
@@ -177,7 +568,7 @@ func (s *Storage) UpdatePeople(name, surname, patronym, gender, nationality stri
 	}
 
 	if gender != "" {
-		genderId, err := s.SaveGender(gender)
+		genderId, err := s.SaveGender(ctx, gender)
 		if err != nil {
 			return fmt.Errorf("%s: %w", op, err)
 		}
@@ -187,7 +578,7 @@ func (s *Storage) UpdatePeople(name, surname, patronym, gender, nationality stri
 	}
 
 	if nationality != "" {
-		nationalityId, err := s.SaveNationality(nationality)
+		nationalityId, err := s.SaveNationality(ctx, nationality)
 		if err != nil {
 			return fmt.Errorf("%s: %w", op, err)
 		}
@@ -201,15 +592,37 @@ func (s *Storage) UpdatePeople(name, surname, patronym, gender, nationality stri
 		query += fmt.Sprintf(" WHERE id = $%d", len(args)+1)
 		args = append(args, id)
 
-		stmt, err := s.db.Prepare(query)
+		updated, err := s.q.Exec(ctx, query, args...)
 		if err != nil {
 			return fmt.Errorf("%s: %w", op, err)
 		}
-		_, err = stmt.Exec(args...)
-		if errors.Is(err, sql.ErrNoRows) {
+
+		if updated == 0 {
+			return storage.ErrPeopleNotFound
+		}
+	} else {
+		// Nothing to change, but a missing person is still reported.
+		var exists bool
+
+		if err := s.q.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM people_info WHERE id = $1)", id).Scan(&exists); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if !exists {
 			return storage.ErrPeopleNotFound
 		}
-		if err != nil {
+	}
+
+	for field, set := range map[string]bool{
+		models.FieldAge:         age != 0,
+		models.FieldGender:      gender != "",
+		models.FieldNationality: nationality != "",
+	} {
+		if !set {
+			continue
+		}
+
+		if err := s.setProvenance(ctx, id, field, models.Provenance{Source: models.SourceManual}); err != nil {
 			return fmt.Errorf("%s: %w", op, err)
 		}
 	}
@@ -217,104 +630,498 @@ func (s *Storage) UpdatePeople(name, surname, patronym, gender, nationality stri
 	return nil
 }
 
-func (s *Storage) GetPeople(limit, offset int64, name, surname, patronym, gender, nationality string, age int) ([]models.People, int64, error) {
+// selectPeople reads people_info rows in the shape scanPeople expects. The
+// score column is filled in by peopleScore.
+const selectPeople = `
+    SELECT people_info.id, name, surname, COALESCE(patronym, ''), COALESCE(age, 0), COALESCE(gender_name, ''), COALESCE(nationality_name, ''), enrichment_status, people_info.created_at, %s,
+        (
+            SELECT json_object_agg(field, json_build_object(
+                'source', source, 'provider', COALESCE(provider, ''), 'probability', probability, 'updatedAt', updated_at
+            ))
+            FROM attribute_provenance WHERE people_id = people_info.id
+        )
+    FROM people_info LEFT JOIN gender ON gender_id = gender.id LEFT JOIN nationality ON nationality_id = nationality.id
+`
+
+func scanPeople(row interface{ Scan(dest ...any) error }) (models.People, error) {
+	var p models.People
+	var provenance []byte
+
+	if err := row.Scan(
+		&p.ID,
+		&p.Name,
+		&p.Surname,
+		&p.Patronymic,
+		&p.Age,
+		&p.Gender,
+		&p.Nationality,
+		&p.EnrichmentStatus,
+		&p.CreatedAt,
+		&p.Score,
+		&provenance,
+	); err != nil {
+		return models.People{}, err
+	}
+
+	if provenance != nil {
+		if err := json.Unmarshal(provenance, &p.Provenance); err != nil {
+			return models.People{}, err
+		}
+	}
+
+	return p, nil
+}
+
+func (s *Storage) GetPeopleByID(ctx context.Context, id int64) (models.People, error) {
+	const op = "storage.postgres.GetPeopleByID"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	p, err := scanPeople(s.q.QueryRow(ctx, s.stmt(stmtGetPeopleByID), id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.People{}, storage.ErrPeopleNotFound
+	}
+	if err != nil {
+		return models.People{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return p, nil
+}
+
+func (s *Storage) GetPeople(ctx context.Context, limit, offset int64, filter models.PeopleFilter, sort []models.SortKey) ([]models.People, int64, error) {
 	const op = "storage.postgres.GetPeople"
 
-	query := `
-        SELECT name, surname, patronym, age, gender_name, nationality_name
-        FROM people_info INNER JOIN gender ON gender_id = gender.id INNER JOIN nationality ON nationality_id = nationality.id
-    `
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	query := fmt.Sprintf(selectPeople, peopleScore(filter))
 
 	queryForTotal := "SELECT COUNT(*) FROM people_info"
 
-	var args []any
-	var cond []string
+	cond, args := peopleConditions(filter)
 
-	if name != "" {
-		cond = append(cond, fmt.Sprintf("name = $%d", len(args)+1))
-		args = append(args, name)
+	if len(cond) > 0 {
+		queryForTotal += " WHERE " + strings.Join(cond, " AND ")
+		query += " WHERE " + strings.Join(cond, " AND ")
 	}
 
-	if surname != "" {
-		cond = append(cond, fmt.Sprintf("surname = $%d", len(args)+1))
-		args = append(args, surname)
-	}
+	countArgs := args
 
-	if patronym != "" {
-		cond = append(cond, fmt.Sprintf("patronym = $%d", len(args)+1))
-		args = append(args, patronym)
-	}
+	query += orderBy(sort, false)
+	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, limit, offset)
 
-	if age != 0 {
-		cond = append(cond, fmt.Sprintf("age = $%d", len(args)+1))
-		args = append(args, age)
+	total, rows, err := s.countAndQuery(ctx, queryForTotal, countArgs, query, args)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, 0, storage.ErrPeopleNotFound
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
 	}
+	defer rows.Close()
 
-	if gender != "" {
-		genderId, err := s.GetGender(gender)
+	var people []models.People
+
+	for rows.Next() {
+		p, err := scanPeople(rows)
 		if err != nil {
 			return nil, 0, fmt.Errorf("%s: %w", op, err)
 		}
 
-		cond = append(cond, fmt.Sprintf("gender_id = $%d", len(args)+1))
-		args = append(args, genderId)
+		people = append(people, p)
 	}
 
-	if nationality != "" {
-		nationalityId, err := s.GetNationality(nationality)
-		if err != nil {
-			return nil, 0, fmt.Errorf("%s: %w", op, err)
-		}
-
-		cond = append(cond, fmt.Sprintf("nationality_id = $%d", len(args)+1))
-		args = append(args, nationalityId)
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
 	}
 
-	if args != nil {
-		queryForTotal += " WHERE " + strings.Join(cond, " AND ")
-		query += " WHERE " + strings.Join(cond, " AND ")
+	return people, total, nil
+}
+
+// countAndQuery runs a count and the query it counts, in one round trip where
+// the backend supports batches.
+func (s *Storage) countAndQuery(ctx context.Context, countQuery string, countArgs []any, query string, args []any) (int64, rows, error) {
+	if b, ok := s.q.(batcher); ok {
+		return b.QueryWithCount(ctx, countQuery, countArgs, query, args)
 	}
 
 	var total int64
 
-	err := s.db.QueryRow(queryForTotal, args...).Scan(&total)
-	if errors.Is(err, sql.ErrNoRows) {
-		return nil, 0, storage.ErrPeopleNotFound
+	if err := s.q.QueryRow(ctx, countQuery, countArgs...).Scan(&total); err != nil {
+		return 0, nil, err
 	}
+
+	r, err := s.q.Query(ctx, query, args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("%s: %w", op, err)
+		return 0, nil, err
 	}
 
-	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
-	args = append(args, limit, offset)
+	return total, r, nil
+}
 
-	rows, err := s.db.Query(query, args...)
-	if errors.Is(err, sql.ErrNoRows) {
-		return nil, 0, storage.ErrPeopleNotFound
+// GetPeopleAfter returns up to limit people following the cursor in sort
+// order, or preceding it for a backward cursor. A cursor without values
+// starts from the beginning. more reports whether rows remain beyond the
+// returned ones in the direction of travel.
+func (s *Storage) GetPeopleAfter(ctx context.Context, limit int64, filter models.PeopleFilter, sort []models.SortKey, cursor models.Cursor) ([]models.People, bool, error) {
+	const op = "storage.postgres.GetPeopleAfter"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	cond, args := peopleConditions(filter)
+
+	if len(cursor.Values) > 0 {
+		c, keysetArgs := keyset(sort, cursor, len(args)+1)
+
+		cond = append(cond, c)
+		args = append(args, keysetArgs...)
 	}
+
+	query := fmt.Sprintf(selectPeople, peopleScore(filter))
+
+	if len(cond) > 0 {
+		query += " WHERE " + strings.Join(cond, " AND ")
+	}
+
+	query += orderBy(sort, cursor.Backward)
+	query += fmt.Sprintf(" LIMIT $%d", len(args)+1)
+	args = append(args, limit+1)
+
+	rows, err := s.q.Query(ctx, query, args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("%s: %w", op, err)
+		return nil, false, fmt.Errorf("%s: %w", op, err)
 	}
 	defer rows.Close()
 
 	var people []models.People
 
 	for rows.Next() {
-		var p models.People
-
-		if err = rows.Scan(
-			&p.Name,
-			&p.Surname,
-			&p.Patronymic,
-			&p.Age,
-			&p.Gender,
-			&p.Nationality,
-		); err != nil {
-			return nil, 0, fmt.Errorf("%s: %w", op, err)
+		p, err := scanPeople(rows)
+		if err != nil {
+			return nil, false, fmt.Errorf("%s: %w", op, err)
 		}
 
 		people = append(people, p)
 	}
 
-	return people, total, nil
+	if err = rows.Err(); err != nil {
+		return nil, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	more := int64(len(people)) > limit
+	if more {
+		people = people[:limit]
+	}
+
+	if cursor.Backward {
+		slices.Reverse(people)
+	}
+
+	return people, more, nil
+}
+
+// fullName is the text searched by a query, as indexed by
+// idx_people_info_full_name_trgm.
+const fullName = "(name || ' ' || surname || ' ' || COALESCE(patronym, ''))"
+
+// peopleScore returns the score column for the filter. The search query is
+// always $1, see peopleConditions.
+func peopleScore(f models.PeopleFilter) string {
+	if f.Query == "" {
+		return "NULL::real"
+	}
+
+	return sortColumns[models.SortScore].expr
+}
+
+// streamFetch is how many rows StreamPeople fetches from its cursor at once.
+const streamFetch = 500
+
+// StreamPeople calls fn for every person matching the filter, in sort order.
+// Rows are read through a server-side cursor, a batch at a time, so memory
+// use does not grow with the result. An error from fn stops the stream and
+// is returned as is.
+func (s *Storage) StreamPeople(ctx context.Context, filter models.PeopleFilter, sort []models.SortKey, fn func(models.People) error) error {
+	const op = "storage.postgres.StreamPeople"
+
+	cond, args := peopleConditions(filter)
+
+	query := fmt.Sprintf(selectPeople, peopleScore(filter))
+
+	if len(cond) > 0 {
+		query += " WHERE " + strings.Join(cond, " AND ")
+	}
+
+	query += orderBy(sort, false)
+
+	// Cursors only live inside a transaction.
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	declareCtx, cancel := s.withTimeout(ctx)
+	_, err = tx.Exec(declareCtx, "SET TRANSACTION READ ONLY")
+	if err == nil {
+		_, err = tx.Exec(declareCtx, "DECLARE people_stream NO SCROLL CURSOR FOR "+query, args...)
+	}
+	cancel()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// fetch passes one batch to fn. The query timeout covers each batch, not
+	// the whole stream.
+	fetch := func() (int, error) {
+		ctx, cancel := s.withTimeout(ctx)
+		defer cancel()
+
+		rows, err := tx.Query(ctx, fmt.Sprintf("FETCH %d FROM people_stream", streamFetch))
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+		defer rows.Close()
+
+		fetched := 0
+
+		for rows.Next() {
+			fetched++
+
+			p, err := scanPeople(rows)
+			if err != nil {
+				return 0, fmt.Errorf("%s: %w", op, err)
+			}
+
+			if err = fn(p); err != nil {
+				return 0, err
+			}
+		}
+
+		if err = rows.Err(); err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+
+		return fetched, nil
+	}
+
+	for {
+		fetched, err := fetch()
+		if err != nil {
+			return err
+		}
+
+		if fetched < streamFetch {
+			break
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// peopleConditions turns the people filter into WHERE conditions over
+// people_info together with their arguments. The search query, if any, comes
+// first so that it is always $1.
+func peopleConditions(f models.PeopleFilter) ([]string, []any) {
+	var args []any
+	var cond []string
+
+	if f.Query != "" {
+		// Whole words hit the full-text index, misspellings the trigram one.
+		cond = append(cond, "(search @@ plainto_tsquery('simple', $1) OR $1 <% "+fullName+")")
+		args = append(args, f.Query)
+	}
+
+	text := func(column string, t models.TextFilter) {
+		if t.Pattern == "" {
+			return
+		}
+
+		var c string
+
+		switch {
+		case strings.Contains(t.Pattern, "*") && t.IgnoreCase:
+			c = fmt.Sprintf("%s ILIKE $%d", column, len(args)+1)
+			args = append(args, likePattern(t.Pattern))
+		case strings.Contains(t.Pattern, "*"):
+			c = fmt.Sprintf("%s LIKE $%d", column, len(args)+1)
+			args = append(args, likePattern(t.Pattern))
+		case t.IgnoreCase:
+			c = fmt.Sprintf("lower(%s) = lower($%d)", column, len(args)+1)
+			args = append(args, t.Pattern)
+		default:
+			c = fmt.Sprintf("%s = $%d", column, len(args)+1)
+			args = append(args, t.Pattern)
+		}
+
+		if t.Negate {
+			c = "NOT (" + c + ")"
+		}
+
+		cond = append(cond, c)
+	}
+
+	set := func(column, table, nameColumn string, sf models.SetFilter) {
+		if len(sf.Values) == 0 {
+			return
+		}
+
+		in := fmt.Sprintf("%s IN (SELECT id FROM %s WHERE %s = ANY($%d))", column, table, nameColumn, len(args)+1)
+		args = append(args, sf.Values)
+
+		if sf.Negate {
+			cond = append(cond, fmt.Sprintf("(%s IS NULL OR NOT %s)", column, in))
+		} else {
+			cond = append(cond, in)
+		}
+	}
+
+	text("name", f.Name)
+	text("surname", f.Surname)
+	text("COALESCE(patronym, '')", f.Patronym)
+
+	if f.Age != 0 {
+		cond = append(cond, fmt.Sprintf("age = $%d", len(args)+1))
+		args = append(args, f.Age)
+	}
+
+	if f.AgeMin != 0 {
+		cond = append(cond, fmt.Sprintf("age >= $%d", len(args)+1))
+		args = append(args, f.AgeMin)
+	}
+
+	if f.AgeMax != 0 {
+		cond = append(cond, fmt.Sprintf("age <= $%d", len(args)+1))
+		args = append(args, f.AgeMax)
+	}
+
+	set("gender_id", "gender", "gender_name", f.Gender)
+	set("nationality_id", "nationality", "nationality_name", f.Nationality)
+
+	if f.Source != "" || f.MaxProbability > 0 {
+		var provenance []string
+
+		if f.Source != "" {
+			provenance = append(provenance, fmt.Sprintf("source = $%d", len(args)+1))
+			args = append(args, f.Source)
+		}
+
+		if f.MaxProbability > 0 {
+			provenance = append(provenance, fmt.Sprintf("probability <= $%d", len(args)+1))
+			args = append(args, f.MaxProbability)
+		}
+
+		cond = append(cond, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM attribute_provenance WHERE people_id = people_info.id AND %s)",
+			strings.Join(provenance, " AND "),
+		))
+	}
+
+	return cond, args
+}
+
+// sortColumn is a sortable expression and the type its cursor values are cast
+// to. Expressions never yield NULL, so keyset comparisons agree with ORDER BY.
+// Text is compared bytewise under the "C" collation, whatever the database
+// default, so the order matches the memory backend.
+type sortColumn struct {
+	expr string
+	typ  string
+}
+
+var sortColumns = map[string]sortColumn{
+	models.SortID:          {"people_info.id", "bigint"},
+	models.SortName:        {`name COLLATE "C"`, "text"},
+	models.SortSurname:     {`surname COLLATE "C"`, "text"},
+	models.SortAge:         {"COALESCE(age, 0)", "integer"},
+	models.SortGender:      {`COALESCE(gender_name, '') COLLATE "C"`, "text"},
+	models.SortNationality: {`COALESCE(nationality_name, '') COLLATE "C"`, "text"},
+	models.SortCreatedAt:   {"people_info.created_at", "timestamptz"},
+	models.SortScore:       {"word_similarity($1, " + fullName + ")", "real"},
+}
+
+// orderBy builds the ORDER BY clause. Rows are always tie-broken on id so
+// pages stay stable. reverse flips every direction.
+func orderBy(sort []models.SortKey, reverse bool) string {
+	var keys []string
+
+	for _, k := range models.StableSort(sort) {
+		column := sortColumns[k.Field].expr
+
+		if k.Desc != reverse {
+			column += " DESC"
+		}
+
+		keys = append(keys, column)
+	}
+
+	return " ORDER BY " + strings.Join(keys, ", ")
+}
+
+// keyset builds the condition selecting rows after the cursor, or before it
+// when the cursor goes backward. Placeholders are numbered from next.
+func keyset(sort []models.SortKey, cursor models.Cursor, next int) (string, []any) {
+	keys := models.StableSort(sort)
+
+	var args []any
+
+	placeholder := func(i int) string {
+		args = append(args, cursor.Values[i])
+
+		// Values travel as text and are cast on the server.
+		return fmt.Sprintf("CAST($%d::text AS %s)", next+len(args)-1, sortColumns[keys[i].Field].typ)
+	}
+
+	op := func(k models.SortKey) string {
+		if k.Desc != cursor.Backward {
+			return "<"
+		}
+
+		return ">"
+	}
+
+	sameDirection := true
+	for _, k := range keys {
+		sameDirection = sameDirection && k.Desc == keys[0].Desc
+	}
+
+	// A row comparison lets Postgres walk a matching index.
+	if sameDirection {
+		var columns, values []string
+
+		for i, k := range keys {
+			columns = append(columns, sortColumns[k.Field].expr)
+			values = append(values, placeholder(i))
+		}
+
+		return fmt.Sprintf("(%s) %s (%s)", strings.Join(columns, ", "), op(keys[0]), strings.Join(values, ", ")), args
+	}
+
+	var or []string
+
+	for i, k := range keys {
+		var and []string
+
+		for j := 0; j < i; j++ {
+			and = append(and, fmt.Sprintf("%s = %s", sortColumns[keys[j].Field].expr, placeholder(j)))
+		}
+
+		and = append(and, fmt.Sprintf("%s %s %s", sortColumns[k.Field].expr, op(k), placeholder(i)))
+		or = append(or, "("+strings.Join(and, " AND ")+")")
+	}
+
+	return "(" + strings.Join(or, " OR ") + ")", args
+}
+
+// likePattern escapes LIKE metacharacters and turns * wildcards into %.
+func likePattern(pattern string) string {
+	r := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`, "*", "%")
+
+	return r.Replace(pattern)
 }
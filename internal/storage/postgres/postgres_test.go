@@ -0,0 +1,245 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/url"
+	"predictor/internal/config"
+	"predictor/internal/domain/models"
+	"predictor/internal/storage"
+	"predictor/internal/storage/postgres"
+	"predictor/internal/storage/storagetest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var drivers = []string{postgres.DriverStdlib, postgres.DriverPgxPool}
+
+// newDatabase creates a throwaway database with the migrations applied and
+// drops it after the test. It returns the storage config for it and a plain
+// connection for test setup.
+func newDatabase(t *testing.T) (config.Storage, *sql.DB) {
+	t.Helper()
+
+	if serverErr != nil {
+		t.Skipf("no postgres, set %s or %s: %v", dsnEnv, requireEnv, serverErr)
+	}
+
+	dsn := serverDSN
+
+	server, err := url.Parse(dsn)
+	require.NoError(t, err)
+
+	admin, err := sql.Open("pgx", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { admin.Close() })
+
+	name := fmt.Sprintf("predictor_test_%d", time.Now().UnixNano())
+
+	_, err = admin.Exec("CREATE DATABASE " + name)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if _, err := admin.Exec("DROP DATABASE IF EXISTS " + name); err != nil {
+			t.Errorf("drop %s: %v", name, err)
+		}
+	})
+
+	u := *server
+	u.Path = "/" + name
+
+	m, err := migrate.New("file://../../../migrations", u.String())
+	require.NoError(t, err)
+
+	if err = m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	srcErr, dbErr := m.Close()
+	require.NoError(t, errors.Join(srcErr, dbErr))
+
+	db, err := sql.Open("pgx", u.String())
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	password, _ := server.User.Password()
+
+	sslMode := server.Query().Get("sslmode")
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+
+	return config.Storage{
+		Address:         server.Host,
+		User:            server.User.Username(),
+		Password:        password,
+		Name:            name,
+		QueryTimeout:    5 * time.Second,
+		SSLMode:         sslMode,
+		ApplicationName: "predictor-test",
+		MaxOpenConns:    5,
+		MaxIdleConns:    2,
+	}, db
+}
+
+// open connects to the database with the driver and closes it after the test.
+func open(t *testing.T, cfg config.Storage, driver string) *postgres.Storage {
+	t.Helper()
+
+	cfg.Driver = driver
+
+	s, err := postgres.New(context.Background(), cfg)
+	require.NoError(t, err)
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// truncate empties every table.
+func truncate(t *testing.T, db *sql.DB) {
+	t.Helper()
+
+	_, err := db.Exec(`
+		TRUNCATE people_info, gender, nationality, people_prediction, nationality_prediction,
+			enrichment_cache, enrichment_job, attribute_provenance, enrichment_change
+		RESTART IDENTITY CASCADE
+	`)
+	require.NoError(t, err)
+}
+
+func TestStorage(t *testing.T) {
+	cfg, db := newDatabase(t)
+
+	for _, driver := range drivers {
+		t.Run(driver, func(t *testing.T) {
+			s := open(t, cfg, driver)
+
+			storagetest.Run(t, func(t *testing.T) storagetest.Storage {
+				truncate(t, db)
+
+				return s
+			})
+		})
+	}
+}
+
+func TestUpdatePeople(t *testing.T) {
+	cfg, db := newDatabase(t)
+
+	// Every combination puts patronym in a different place of the SET list.
+	tests := []struct {
+		name                      string
+		surname, patronym, gender string
+		age                       int
+	}{
+		{name: "patronym only", patronym: "Olegovich"},
+		{name: "after surname", surname: "Petrov", patronym: "Olegovich"},
+		{name: "before age and gender", patronym: "Olegovich", age: 40, gender: "male"},
+	}
+
+	for _, driver := range drivers {
+		s := open(t, cfg, driver)
+
+		for _, tt := range tests {
+			t.Run(driver+"/"+tt.name, func(t *testing.T) {
+				ctx := context.Background()
+
+				truncate(t, db)
+
+				saved, err := s.SavePeople(ctx, "Ivan", "Ivanov", "Petrovich")
+				require.NoError(t, err)
+
+				require.NoError(t, s.UpdatePeople(ctx, "", tt.surname, tt.patronym, tt.gender, "", tt.age, saved.ID))
+
+				p, err := s.GetPeopleByID(ctx, saved.ID)
+				require.NoError(t, err)
+
+				assert.Equal(t, tt.patronym, p.Patronymic)
+				assert.Equal(t, "Ivan", p.Name)
+
+				if tt.surname != "" {
+					assert.Equal(t, tt.surname, p.Surname)
+				}
+
+				assert.Equal(t, tt.age, p.Age)
+				assert.Equal(t, tt.gender, p.Gender)
+
+				err = s.UpdatePeople(ctx, "", "", tt.patronym, "", "", 0, saved.ID+1)
+				assert.ErrorIs(t, err, storage.ErrPeopleNotFound)
+
+				err = s.DeletePeople(ctx, saved.ID+1)
+				assert.ErrorIs(t, err, storage.ErrPeopleNotFound)
+			})
+		}
+	}
+}
+
+// Imports running at once must agree on the ids of genders and nationalities
+// none of them has seen before.
+func TestImportPeopleConcurrently(t *testing.T) {
+	cfg, db := newDatabase(t)
+
+	for _, driver := range drivers {
+		t.Run(driver, func(t *testing.T) {
+			ctx := context.Background()
+			s := open(t, cfg, driver)
+
+			truncate(t, db)
+
+			const imports = 8
+
+			var wg sync.WaitGroup
+			errs := make(chan error, imports)
+
+			for i := range imports {
+				wg.Add(1)
+
+				go func() {
+					defer wg.Done()
+
+					_, err := s.ImportPeople(ctx, []models.People{
+						{Name: fmt.Sprintf("Ivan%d", i), Surname: "Ivanov", Age: 30, Gender: "male", Nationality: "RU"},
+						{Name: fmt.Sprintf("Anna%d", i), Surname: "Ivanova", Age: 25, Gender: "female", Nationality: "RU"},
+					}, false)
+					errs <- err
+				}()
+			}
+
+			wg.Wait()
+			close(errs)
+
+			for err := range errs {
+				require.NoError(t, err)
+			}
+
+			var genders, nationalities int
+
+			require.NoError(t, db.QueryRow("SELECT count(*) FROM gender").Scan(&genders))
+			require.NoError(t, db.QueryRow("SELECT count(*) FROM nationality").Scan(&nationalities))
+
+			assert.Equal(t, 2, genders)
+			assert.Equal(t, 1, nationalities)
+
+			people, total, err := s.GetPeople(ctx, 100, 0, models.PeopleFilter{
+				Gender:      models.SetFilter{Values: []string{"male"}},
+				Nationality: models.SetFilter{Values: []string{"RU"}},
+			}, nil)
+			require.NoError(t, err)
+
+			assert.Equal(t, int64(imports), total)
+
+			for _, p := range people {
+				assert.True(t, strings.HasPrefix(p.Name, "Ivan"), p.Name)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package postgres
+
+import "fmt"
+
+// Names of the statements run most often. The pgxpool backend prepares them
+// on every connection and runs them by name, database/sql runs their text.
+const (
+	stmtSavePeople          = "save_people"
+	stmtGetPeopleByID       = "get_people_by_id"
+	stmtDeletePeople        = "delete_people"
+	stmtClaimEnrichmentJob  = "claim_enrichment_job"
+	stmtDeleteEnrichmentJob = "delete_enrichment_job"
+	stmtGetCached           = "get_cached"
+	stmtSaveCached          = "save_cached"
+)
+
+var statements = map[string]string{
+	// The person is returned in the shape scanPeople expects.
+	stmtSavePeople: `
+		WITH people AS (
+			INSERT INTO people_info(name, surname, patronym, enrichment_status)
+			VALUES ($1, $2, $3, 'pending')
+			RETURNING id, name, surname, patronym, enrichment_status, created_at
+		), job AS (
+			INSERT INTO enrichment_job(people_id)
+			SELECT id FROM people
+		)
+		SELECT id, name, surname, COALESCE(patronym, ''), 0, '', '', enrichment_status, created_at, NULL::real, NULL::json
+		FROM people
+	`,
+	stmtGetPeopleByID: fmt.Sprintf(selectPeople, "NULL::real") + " WHERE people_info.id = $1",
+	stmtDeletePeople:  "DELETE FROM people_info WHERE id = $1",
+	stmtClaimEnrichmentJob: `
+		WITH job AS (
+			UPDATE enrichment_job
+			SET attempts = attempts + 1, locked_until = now() + make_interval(secs => $1)
+			WHERE id = (
+				SELECT id FROM enrichment_job
+				WHERE run_at <= now() AND (locked_until IS NULL OR locked_until < now())
+				ORDER BY run_at
+				LIMIT 1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING id, people_id, attempts, overwrite_manual
+		)
+		SELECT job.id, job.people_id, job.attempts, job.overwrite_manual, people_info.name
+		FROM job INNER JOIN people_info ON people_info.id = job.people_id
+	`,
+	stmtDeleteEnrichmentJob: "DELETE FROM enrichment_job WHERE id = $1",
+	stmtGetCached: `
+		SELECT payload FROM enrichment_cache
+		WHERE kind = $1 AND name = $2 AND locale = $3 AND expires_at > now()
+	`,
+	stmtSaveCached: `
+		INSERT INTO enrichment_cache(kind, name, locale, payload, expires_at)
+		VALUES ($1, $2, $3, $4, now() + make_interval(secs => $5))
+		ON CONFLICT (kind, name, locale) DO UPDATE SET
+			payload = EXCLUDED.payload,
+			expires_at = EXCLUDED.expires_at
+	`,
+}
+
+// stmt returns what to run for a named statement on this backend.
+func (s *Storage) stmt(name string) string {
+	if s.prepared {
+		return name
+	}
+
+	return statements[name]
+}
@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"predictor/internal/config"
+)
+
+// sqlDB is the database/sql backend.
+type sqlDB struct {
+	sqlConn
+	db *sql.DB
+}
+
+func openStdlib(cfgStorage config.Storage) (*sqlDB, error) {
+	db, err := sql.Open("pgx", DSN(cfgStorage))
+	if err != nil {
+		return nil, err
+	}
+
+	db.SetMaxOpenConns(cfgStorage.MaxOpenConns)
+	db.SetMaxIdleConns(cfgStorage.MaxIdleConns)
+	db.SetConnMaxLifetime(cfgStorage.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfgStorage.ConnMaxIdleTime)
+
+	return &sqlDB{sqlConn: sqlConn{db}, db: db}, nil
+}
+
+func (d *sqlDB) Begin(ctx context.Context) (transaction, error) {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sqlTx{sqlConn: sqlConn{tx}, tx: tx}, nil
+}
+
+func (d *sqlDB) Ping(ctx context.Context) error {
+	return d.db.PingContext(ctx)
+}
+
+func (d *sqlDB) Close() {
+	_ = d.db.Close()
+}
+
+type sqlTx struct {
+	sqlConn
+	tx *sql.Tx
+}
+
+func (t *sqlTx) Commit(context.Context) error {
+	return t.tx.Commit()
+}
+
+func (t *sqlTx) Rollback(context.Context) error {
+	return t.tx.Rollback()
+}
+
+// sqlQuerier is what *sql.DB and *sql.Tx have in common.
+type sqlQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type sqlConn struct {
+	q sqlQuerier
+}
+
+func (c sqlConn) Exec(ctx context.Context, query string, args ...any) (int64, error) {
+	res, err := c.q.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+func (c sqlConn) Query(ctx context.Context, query string, args ...any) (rows, error) {
+	r, err := c.q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return sqlRows{r}, nil
+}
+
+func (c sqlConn) QueryRow(ctx context.Context, query string, args ...any) row {
+	return c.q.QueryRowContext(ctx, query, args...)
+}
+
+type sqlRows struct {
+	*sql.Rows
+}
+
+func (r sqlRows) Close() {
+	_ = r.Rows.Close()
+}
@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+)
+
+// querier runs queries on the pool or inside a transaction, whichever the
+// backend.
+type querier interface {
+	// Exec returns the number of rows affected.
+	Exec(ctx context.Context, query string, args ...any) (int64, error)
+	Query(ctx context.Context, query string, args ...any) (rows, error)
+	QueryRow(ctx context.Context, query string, args ...any) row
+}
+
+type rows interface {
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+	Close()
+}
+
+type row interface {
+	Scan(dest ...any) error
+}
+
+// database is a connection pool of one of the backends.
+type database interface {
+	querier
+	Begin(ctx context.Context) (transaction, error)
+	Ping(ctx context.Context) error
+	Close()
+}
+
+type transaction interface {
+	querier
+	Commit(ctx context.Context) error
+	Rollback(ctx context.Context) error
+}
+
+// copier is implemented by backends that can bulk load rows with COPY.
+type copier interface {
+	CopyFrom(ctx context.Context, table string, columns []string, rows [][]any) (int64, error)
+}
+
+// batcher is implemented by backends that can send a count and the page it
+// counts in one round trip.
+type batcher interface {
+	QueryWithCount(ctx context.Context, countQuery string, countArgs []any, query string, args []any) (int64, rows, error)
+}
+
+// WithTx runs fn in a transaction, committing when fn returns nil and rolling
+// back otherwise or when ctx is done. Storage methods called on tx run inside
+// the transaction. On a Storage that is already in one, fn simply joins it.
+func (s *Storage) WithTx(ctx context.Context, fn func(tx *Storage) error) error {
+	const op = "storage.postgres.WithTx"
+
+	if _, ok := s.q.(transaction); ok {
+		return fn(s)
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	// A no-op once committed, and a safety net if fn panics.
+	defer tx.Rollback(ctx)
+
+	inTx := *s
+	inTx.q = tx
+
+	if err = fn(&inTx); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// withTimeout bounds a storage call by the configured query timeout.
+func (s *Storage) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, s.timeout)
+}
@@ -2,6 +2,14 @@ package storage
 
 import "errors"
 
+// Storage drivers, selected with STORAGE_DRIVER.
+const (
+	DriverPostgres = "postgres"
+	DriverMemory   = "memory"
+)
+
 var (
 	ErrPeopleNotFound = errors.New("people not found")
+	ErrCacheMiss      = errors.New("cache miss")
+	ErrNoJobs         = errors.New("no enrichment jobs")
 )
@@ -0,0 +1,572 @@
+// Package storagetest checks that a storage driver behaves like the others.
+// Drivers run the same suite from their own tests, e.g.
+//
+//	storagetest.Run(t, func(t *testing.T) storagetest.Storage { return memory.New() })
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"predictor/internal/domain/models"
+	"predictor/internal/lib/query"
+	"predictor/internal/storage"
+	"testing"
+	"time"
+)
+
+// Storage is what the service needs from a storage driver.
+type Storage interface {
+	SavePeople(ctx context.Context, name, surname, patronym string) (models.People, error)
+	SavePeopleBatch(ctx context.Context, people []models.People) ([]int64, error)
+	ImportPeople(ctx context.Context, people []models.People, enrich bool) ([]int64, error)
+	GetPeopleByID(ctx context.Context, id int64) (models.People, error)
+	GetPeople(ctx context.Context, limit, offset int64, filter models.PeopleFilter, sort []models.SortKey) ([]models.People, int64, error)
+	GetPeopleAfter(ctx context.Context, limit int64, filter models.PeopleFilter, sort []models.SortKey, cursor models.Cursor) ([]models.People, bool, error)
+	StreamPeople(ctx context.Context, filter models.PeopleFilter, sort []models.SortKey, fn func(models.People) error) error
+	UpdatePeople(ctx context.Context, name, surname, patronym, gender, nationality string, age int, id int64) error
+	DeletePeople(ctx context.Context, id int64) error
+
+	ClaimEnrichmentJob(ctx context.Context, lease time.Duration) (models.EnrichmentJob, error)
+	CompleteEnrichment(ctx context.Context, job models.EnrichmentJob, prediction models.Prediction) error
+	RetryEnrichmentJob(ctx context.Context, job models.EnrichmentJob, delay time.Duration, reason string) error
+	FailEnrichment(ctx context.Context, job models.EnrichmentJob) error
+	EnqueueEnrichment(ctx context.Context, overwriteManual bool, filter models.PeopleFilter) (int64, error)
+	ApplyEnrichment(ctx context.Context, id int64, prediction models.Prediction, overwriteManual bool) (models.EnrichmentResult, error)
+
+	GetCached(ctx context.Context, kind, name, locale string) ([]byte, error)
+	SaveCached(ctx context.Context, kind, name, locale string, payload []byte, ttl time.Duration) error
+	PurgeExpiredCache(ctx context.Context) (int64, error)
+}
+
+// Factory returns an empty storage for one test.
+type Factory func(t *testing.T) Storage
+
+// Run runs the suite against storages made by newStorage.
+func Run(t *testing.T, newStorage Factory) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, s Storage)
+	}{
+		{"SavePeople", testSavePeople},
+		{"SavePeopleBatch", testSavePeopleBatch},
+		{"ImportPeople", testImportPeople},
+		{"Filters", testFilters},
+		{"Sort", testSort},
+		{"Offset", testOffset},
+		{"Cursor", testCursor},
+		{"Search", testSearch},
+		{"Stream", testStream},
+		{"Update", testUpdate},
+		{"Delete", testDelete},
+		{"Jobs", testJobs},
+		{"ManualAttributes", testManualAttributes},
+		{"Cache", testCache},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, newStorage(t))
+		})
+	}
+}
+
+// seed imports a fixed set of people without enrichment and returns their
+// ids in order.
+func seed(t *testing.T, s Storage) []int64 {
+	t.Helper()
+
+	ids, err := s.ImportPeople(context.Background(), []models.People{
+		{Name: "Ivan", Surname: "Ivanov", Patronymic: "Petrovich", Age: 30, Gender: "male", Nationality: "RU"},
+		{Name: "Anna", Surname: "Smirnova", Age: 25, Gender: "female", Nationality: "UA"},
+		{Name: "ivan", Surname: "Sidorov", Age: 41, Gender: "male", Nationality: "BY"},
+		{Name: "Maria", Surname: "Ivanova"},
+		{Name: "Boris", Surname: "Zaitsev", Age: 52, Gender: "male", Nationality: "RU"},
+	}, false)
+	require.NoError(t, err)
+	require.Len(t, ids, 5)
+
+	return ids
+}
+
+func idsOf(people []models.People) []int64 {
+	ids := make([]int64, 0, len(people))
+	for _, p := range people {
+		ids = append(ids, p.ID)
+	}
+
+	return ids
+}
+
+// pick returns ids at the given positions.
+func pick(ids []int64, positions ...int) []int64 {
+	picked := make([]int64, 0, len(positions))
+	for _, i := range positions {
+		picked = append(picked, ids[i])
+	}
+
+	return picked
+}
+
+func byID() []models.SortKey {
+	return []models.SortKey{{Field: models.SortID}}
+}
+
+func testSavePeople(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	saved, err := s.SavePeople(ctx, "Ivan", "Ivanov", "Petrovich")
+	require.NoError(t, err)
+
+	assert.NotZero(t, saved.ID)
+	assert.Equal(t, "Ivan", saved.Name)
+	assert.Equal(t, "Ivanov", saved.Surname)
+	assert.Equal(t, "Petrovich", saved.Patronymic)
+	assert.Equal(t, models.EnrichmentPending, saved.EnrichmentStatus)
+	assert.False(t, saved.CreatedAt.IsZero())
+
+	got, err := s.GetPeopleByID(ctx, saved.ID)
+	require.NoError(t, err)
+
+	assert.Equal(t, saved.ID, got.ID)
+	assert.Equal(t, saved.Patronymic, got.Patronymic)
+	assert.Equal(t, saved.EnrichmentStatus, got.EnrichmentStatus)
+	assert.True(t, saved.CreatedAt.Equal(got.CreatedAt))
+
+	_, err = s.GetPeopleByID(ctx, saved.ID+1000)
+	assert.ErrorIs(t, err, storage.ErrPeopleNotFound)
+}
+
+func testSavePeopleBatch(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	ids, err := s.SavePeopleBatch(ctx, []models.People{
+		{Name: "Ivan", Surname: "Ivanov"},
+		{Name: "Anna", Surname: "Smirnova", Patronymic: "Olegovna"},
+	})
+	require.NoError(t, err)
+	require.Len(t, ids, 2)
+
+	for i, name := range []string{"Ivan", "Anna"} {
+		p, err := s.GetPeopleByID(ctx, ids[i])
+		require.NoError(t, err)
+
+		assert.Equal(t, name, p.Name)
+		assert.Equal(t, models.EnrichmentPending, p.EnrichmentStatus)
+	}
+}
+
+func testImportPeople(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	ids, err := s.ImportPeople(ctx, []models.People{
+		{Name: "Ivan", Surname: "Ivanov", Age: 30, Gender: "male", Nationality: "RU"},
+		{Name: "Anna", Surname: "Smirnova", Gender: "female"},
+	}, true)
+	require.NoError(t, err)
+	require.Len(t, ids, 2)
+
+	complete, err := s.GetPeopleByID(ctx, ids[0])
+	require.NoError(t, err)
+
+	assert.Equal(t, 30, complete.Age)
+	assert.Equal(t, "male", complete.Gender)
+	assert.Equal(t, "RU", complete.Nationality)
+	assert.Equal(t, models.EnrichmentComplete, complete.EnrichmentStatus)
+
+	partial, err := s.GetPeopleByID(ctx, ids[1])
+	require.NoError(t, err)
+
+	assert.Equal(t, "female", partial.Gender)
+	assert.Equal(t, models.EnrichmentPending, partial.EnrichmentStatus)
+
+	job, err := s.ClaimEnrichmentJob(ctx, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, ids[1], job.PeopleID)
+
+	_, err = s.ClaimEnrichmentJob(ctx, time.Minute)
+	assert.ErrorIs(t, err, storage.ErrNoJobs)
+}
+
+func testFilters(t *testing.T, s Storage) {
+	ids := seed(t, s)
+
+	tests := []struct {
+		name   string
+		filter models.PeopleFilter
+		want   []int64
+	}{
+		{"all", models.PeopleFilter{}, ids},
+		{"name", models.PeopleFilter{Name: models.TextFilter{Pattern: "Ivan"}}, pick(ids, 0)},
+		{"name ignoring case", models.PeopleFilter{Name: models.TextFilter{Pattern: "IVAN", IgnoreCase: true}}, pick(ids, 0, 2)},
+		{"surname prefix", models.PeopleFilter{Surname: models.TextFilter{Pattern: "Ivanov*"}}, pick(ids, 0, 3)},
+		{"surname substring ignoring case", models.PeopleFilter{Surname: models.TextFilter{Pattern: "*IDO*", IgnoreCase: true}}, pick(ids, 2)},
+		{"negated surname", models.PeopleFilter{Surname: models.TextFilter{Pattern: "Ivanov*", Negate: true}}, pick(ids, 1, 2, 4)},
+		{"patronym", models.PeopleFilter{Patronym: models.TextFilter{Pattern: "Petro*"}}, pick(ids, 0)},
+		{"gender", models.PeopleFilter{Gender: models.SetFilter{Values: []string{"male"}}}, pick(ids, 0, 2, 4)},
+		{"nationalities", models.PeopleFilter{Nationality: models.SetFilter{Values: []string{"UA", "BY"}}}, pick(ids, 1, 2)},
+		{"negated nationality", models.PeopleFilter{Nationality: models.SetFilter{Values: []string{"RU"}, Negate: true}}, pick(ids, 1, 2, 3)},
+		{"age", models.PeopleFilter{Age: 30}, pick(ids, 0)},
+		{"age range", models.PeopleFilter{AgeMin: 30, AgeMax: 50}, pick(ids, 0, 2)},
+		{"combined", models.PeopleFilter{Gender: models.SetFilter{Values: []string{"male"}}, AgeMin: 40}, pick(ids, 2, 4)},
+		{"nothing", models.PeopleFilter{Name: models.TextFilter{Pattern: "Nobody"}}, []int64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			people, total, err := s.GetPeople(context.Background(), 100, 0, tt.filter, byID())
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, idsOf(people))
+			assert.Equal(t, int64(len(tt.want)), total)
+		})
+	}
+}
+
+func testSort(t *testing.T, s Storage) {
+	ids := seed(t, s)
+
+	tests := []struct {
+		name string
+		sort []models.SortKey
+		want []int64
+	}{
+		// Text sorts bytewise, so lower case goes after upper case.
+		{"name", []models.SortKey{{Field: models.SortName}}, pick(ids, 1, 4, 0, 3, 2)},
+		{"surname descending", []models.SortKey{{Field: models.SortSurname, Desc: true}}, pick(ids, 4, 1, 2, 3, 0)},
+		{"age descending", []models.SortKey{{Field: models.SortAge, Desc: true}}, pick(ids, 4, 2, 0, 1, 3)},
+		{"gender then age", []models.SortKey{{Field: models.SortGender}, {Field: models.SortAge, Desc: true}}, pick(ids, 3, 1, 4, 2, 0)},
+		{"nationality ties on id", []models.SortKey{{Field: models.SortNationality}}, pick(ids, 3, 2, 0, 4, 1)},
+		{"id descending", []models.SortKey{{Field: models.SortID, Desc: true}}, pick(ids, 4, 3, 2, 1, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			people, _, err := s.GetPeople(context.Background(), 100, 0, models.PeopleFilter{}, tt.sort)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, idsOf(people))
+		})
+	}
+}
+
+func testOffset(t *testing.T, s Storage) {
+	ids := seed(t, s)
+
+	people, total, err := s.GetPeople(context.Background(), 2, 1, models.PeopleFilter{}, byID())
+	require.NoError(t, err)
+
+	assert.Equal(t, pick(ids, 1, 2), idsOf(people))
+	assert.Equal(t, int64(5), total)
+
+	people, total, err = s.GetPeople(context.Background(), 2, 10, models.PeopleFilter{}, byID())
+	require.NoError(t, err)
+
+	assert.Empty(t, people)
+	assert.Equal(t, int64(5), total)
+}
+
+func testCursor(t *testing.T, s Storage) {
+	ctx := context.Background()
+	ids := seed(t, s)
+
+	sort := []models.SortKey{{Field: models.SortName}}
+	filter := models.PeopleFilter{}
+
+	var pages [][]int64
+	var cursor models.Cursor
+
+	for {
+		people, more, err := s.GetPeopleAfter(ctx, 2, filter, sort, cursor)
+		require.NoError(t, err)
+
+		pages = append(pages, idsOf(people))
+
+		if !more {
+			break
+		}
+
+		cursor = query.NewCursor(people[len(people)-1], sort, false)
+	}
+
+	assert.Equal(t, [][]int64{pick(ids, 1, 4), pick(ids, 0, 3), pick(ids, 2)}, pages)
+
+	// Going back from the last page returns the rows before it, in order.
+	last, err := s.GetPeopleByID(ctx, ids[2])
+	require.NoError(t, err)
+
+	people, more, err := s.GetPeopleAfter(ctx, 2, filter, sort, query.NewCursor(last, sort, true))
+	require.NoError(t, err)
+
+	assert.Equal(t, pick(ids, 0, 3), idsOf(people))
+	assert.True(t, more)
+
+	first, err := s.GetPeopleByID(ctx, ids[1])
+	require.NoError(t, err)
+
+	people, more, err = s.GetPeopleAfter(ctx, 2, filter, sort, query.NewCursor(first, sort, true))
+	require.NoError(t, err)
+
+	assert.Empty(t, people)
+	assert.False(t, more)
+
+	// Mixed directions page the same way.
+	mixed := []models.SortKey{{Field: models.SortGender}, {Field: models.SortAge, Desc: true}}
+
+	people, more, err = s.GetPeopleAfter(ctx, 3, filter, mixed, models.Cursor{})
+	require.NoError(t, err)
+	require.True(t, more)
+	assert.Equal(t, pick(ids, 3, 1, 4), idsOf(people))
+
+	people, more, err = s.GetPeopleAfter(ctx, 3, filter, mixed, query.NewCursor(people[2], mixed, false))
+	require.NoError(t, err)
+
+	assert.Equal(t, pick(ids, 2, 0), idsOf(people))
+	assert.False(t, more)
+}
+
+func testSearch(t *testing.T, s Storage) {
+	ids := seed(t, s)
+
+	filter := models.PeopleFilter{Query: "ivan"}
+	sort := []models.SortKey{{Field: models.SortScore, Desc: true}}
+
+	people, total, err := s.GetPeople(context.Background(), 100, 0, filter, sort)
+	require.NoError(t, err)
+
+	// Whole words match exactly, "Ivanova" is close enough.
+	assert.Equal(t, pick(ids, 0, 2, 3), idsOf(people))
+	assert.Equal(t, int64(3), total)
+
+	for _, p := range people {
+		require.NotNil(t, p.Score)
+	}
+
+	assert.InDelta(t, 1, *people[0].Score, 1e-6)
+	assert.Less(t, *people[2].Score, *people[0].Score)
+
+	people, _, err = s.GetPeople(context.Background(), 100, 0, models.PeopleFilter{Query: "zaitsev boris"}, sort)
+	require.NoError(t, err)
+
+	assert.Equal(t, pick(ids, 4), idsOf(people))
+}
+
+func testStream(t *testing.T, s Storage) {
+	ids := seed(t, s)
+
+	filter := models.PeopleFilter{Gender: models.SetFilter{Values: []string{"male"}}}
+	sort := []models.SortKey{{Field: models.SortAge}}
+
+	var streamed []int64
+
+	err := s.StreamPeople(context.Background(), filter, sort, func(p models.People) error {
+		streamed = append(streamed, p.ID)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, pick(ids, 0, 2, 4), streamed)
+
+	stop := errors.New("stop")
+	calls := 0
+
+	err = s.StreamPeople(context.Background(), filter, sort, func(models.People) error {
+		calls++
+
+		return stop
+	})
+
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 1, calls)
+}
+
+func testUpdate(t *testing.T, s Storage) {
+	ctx := context.Background()
+	ids := seed(t, s)
+
+	require.NoError(t, s.UpdatePeople(ctx, "", "", "Ivanovich", "", "", 0, ids[0]))
+
+	p, err := s.GetPeopleByID(ctx, ids[0])
+	require.NoError(t, err)
+
+	assert.Equal(t, "Ivan", p.Name)
+	assert.Equal(t, "Ivanov", p.Surname)
+	assert.Equal(t, "Ivanovich", p.Patronymic)
+	assert.Equal(t, 30, p.Age)
+
+	require.NoError(t, s.UpdatePeople(ctx, "Ioann", "", "", "female", "KZ", 31, ids[0]))
+
+	p, err = s.GetPeopleByID(ctx, ids[0])
+	require.NoError(t, err)
+
+	assert.Equal(t, "Ioann", p.Name)
+	assert.Equal(t, "Ivanovich", p.Patronymic)
+	assert.Equal(t, 31, p.Age)
+	assert.Equal(t, "female", p.Gender)
+	assert.Equal(t, "KZ", p.Nationality)
+
+	for _, field := range []string{models.FieldAge, models.FieldGender, models.FieldNationality} {
+		assert.Equal(t, models.SourceManual, p.Provenance[field].Source, field)
+	}
+
+	manual, _, err := s.GetPeople(ctx, 100, 0, models.PeopleFilter{Source: models.SourceManual}, byID())
+	require.NoError(t, err)
+
+	assert.Equal(t, pick(ids, 0), idsOf(manual))
+
+	// Nothing to change still tells whether the person exists.
+	assert.NoError(t, s.UpdatePeople(ctx, "", "", "", "", "", 0, ids[1]))
+
+	missing := ids[4] + 1000
+
+	assert.ErrorIs(t, s.UpdatePeople(ctx, "Nobody", "", "", "", "", 0, missing), storage.ErrPeopleNotFound)
+	assert.ErrorIs(t, s.UpdatePeople(ctx, "", "", "", "", "", 0, missing), storage.ErrPeopleNotFound)
+}
+
+func testDelete(t *testing.T, s Storage) {
+	ctx := context.Background()
+	ids := seed(t, s)
+
+	require.NoError(t, s.DeletePeople(ctx, ids[1]))
+
+	_, err := s.GetPeopleByID(ctx, ids[1])
+	assert.ErrorIs(t, err, storage.ErrPeopleNotFound)
+
+	assert.ErrorIs(t, s.DeletePeople(ctx, ids[1]), storage.ErrPeopleNotFound)
+
+	people, total, err := s.GetPeople(ctx, 100, 0, models.PeopleFilter{}, byID())
+	require.NoError(t, err)
+
+	assert.Equal(t, pick(ids, 0, 2, 3, 4), idsOf(people))
+	assert.Equal(t, int64(4), total)
+}
+
+func prediction() models.Prediction {
+	return models.Prediction{
+		Age:    models.AgePrediction{Provider: "agify", Age: 33, Count: 10},
+		Gender: models.GenderPrediction{Provider: "genderize", Gender: "male", Probability: 0.9, Count: 10},
+		Nationality: models.NationalityPrediction{Provider: "nationalize", Countries: []models.CountryProbability{
+			{CountryID: "UA", Probability: 0.2},
+			{CountryID: "RU", Probability: 0.7},
+		}},
+	}
+}
+
+func testJobs(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	saved, err := s.SavePeople(ctx, "Ivan", "Ivanov", "")
+	require.NoError(t, err)
+
+	job, err := s.ClaimEnrichmentJob(ctx, time.Minute)
+	require.NoError(t, err)
+
+	assert.Equal(t, saved.ID, job.PeopleID)
+	assert.Equal(t, "Ivan", job.Name)
+	assert.Equal(t, 1, job.Attempts)
+	assert.False(t, job.OverwriteManual)
+
+	// A claimed job is leased.
+	_, err = s.ClaimEnrichmentJob(ctx, time.Minute)
+	assert.ErrorIs(t, err, storage.ErrNoJobs)
+
+	require.NoError(t, s.RetryEnrichmentJob(ctx, job, 0, "upstream failed"))
+
+	job, err = s.ClaimEnrichmentJob(ctx, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 2, job.Attempts)
+
+	require.NoError(t, s.CompleteEnrichment(ctx, job, prediction()))
+
+	p, err := s.GetPeopleByID(ctx, saved.ID)
+	require.NoError(t, err)
+
+	assert.Equal(t, models.EnrichmentComplete, p.EnrichmentStatus)
+	assert.Equal(t, 33, p.Age)
+	assert.Equal(t, "male", p.Gender)
+	assert.Equal(t, "RU", p.Nationality)
+	assert.Equal(t, models.SourcePredicted, p.Provenance[models.FieldGender].Source)
+	assert.Equal(t, "genderize", p.Provenance[models.FieldGender].Provider)
+
+	_, err = s.ClaimEnrichmentJob(ctx, time.Minute)
+	assert.ErrorIs(t, err, storage.ErrNoJobs)
+
+	// Re-enrichment queues matching people again.
+	queued, err := s.EnqueueEnrichment(ctx, true, models.PeopleFilter{Name: models.TextFilter{Pattern: "Ivan"}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), queued)
+
+	p, err = s.GetPeopleByID(ctx, saved.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.EnrichmentPending, p.EnrichmentStatus)
+
+	job, err = s.ClaimEnrichmentJob(ctx, time.Minute)
+	require.NoError(t, err)
+
+	assert.Equal(t, saved.ID, job.PeopleID)
+	assert.Equal(t, 1, job.Attempts)
+	assert.True(t, job.OverwriteManual)
+
+	require.NoError(t, s.FailEnrichment(ctx, job))
+
+	p, err = s.GetPeopleByID(ctx, saved.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.EnrichmentFailed, p.EnrichmentStatus)
+
+	_, err = s.ClaimEnrichmentJob(ctx, time.Minute)
+	assert.ErrorIs(t, err, storage.ErrNoJobs)
+}
+
+func testManualAttributes(t *testing.T, s Storage) {
+	ctx := context.Background()
+	ids := seed(t, s)
+
+	require.NoError(t, s.UpdatePeople(ctx, "", "", "", "female", "", 0, ids[3]))
+
+	result, err := s.ApplyEnrichment(ctx, ids[3], prediction(), false)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{models.FieldGender}, result.Skipped)
+	assert.ElementsMatch(t, []models.AttributeChange{
+		{Field: models.FieldAge, Old: "", New: "33"},
+		{Field: models.FieldNationality, Old: "", New: "RU"},
+	}, result.Changes)
+
+	p, err := s.GetPeopleByID(ctx, ids[3])
+	require.NoError(t, err)
+
+	assert.Equal(t, "female", p.Gender)
+	assert.Equal(t, 33, p.Age)
+
+	result, err = s.ApplyEnrichment(ctx, ids[3], prediction(), true)
+	require.NoError(t, err)
+
+	assert.Empty(t, result.Skipped)
+	assert.Equal(t, []models.AttributeChange{{Field: models.FieldGender, Old: "female", New: "male"}}, result.Changes)
+
+	_, err = s.ApplyEnrichment(ctx, ids[4]+1000, prediction(), false)
+	assert.ErrorIs(t, err, storage.ErrPeopleNotFound)
+}
+
+func testCache(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	_, err := s.GetCached(ctx, "age", "ivan", "")
+	assert.ErrorIs(t, err, storage.ErrCacheMiss)
+
+	require.NoError(t, s.SaveCached(ctx, "age", "ivan", "", []byte(`{"age":33}`), time.Hour))
+	require.NoError(t, s.SaveCached(ctx, "age", "anna", "", []byte(`{"age":25}`), -time.Second))
+
+	payload, err := s.GetCached(ctx, "age", "ivan", "")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"age":33}`, string(payload))
+
+	_, err = s.GetCached(ctx, "age", "anna", "")
+	assert.ErrorIs(t, err, storage.ErrCacheMiss)
+
+	purged, err := s.PurgeExpiredCache(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+}
@@ -0,0 +1,54 @@
+package cachepurge
+
+import (
+	"context"
+	"log/slog"
+	"predictor/internal/lib/logger/sLogger"
+	"time"
+)
+
+type CachePurger interface {
+	PurgeExpiredCache(ctx context.Context) (int64, error)
+}
+
+// Purger deletes expired enrichment cache entries at a fixed interval.
+type Purger struct {
+	log      *slog.Logger
+	store    CachePurger
+	interval time.Duration
+}
+
+func New(log *slog.Logger, store CachePurger, interval time.Duration) *Purger {
+	return &Purger{
+		log:      log.With(slog.String("component", "worker/cachepurge")),
+		store:    store,
+		interval: interval,
+	}
+}
+
+// Run blocks until ctx is done. A non-positive interval disables purging.
+func (p *Purger) Run(ctx context.Context) {
+	if p.interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		purged, err := p.store.PurgeExpiredCache(ctx)
+		if err != nil {
+			p.log.Error("failed to purge cache", sLogger.Error(err))
+
+			continue
+		}
+
+		p.log.Debug("cache purged", slog.Int64("purged", purged))
+	}
+}
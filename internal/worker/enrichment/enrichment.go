@@ -0,0 +1,168 @@
+package enrichment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"predictor/internal/config"
+	"predictor/internal/domain/models"
+	"predictor/internal/lib/logger/sLogger"
+	"predictor/internal/storage"
+	"sync"
+	"time"
+)
+
+type Enricher interface {
+	Enrich(ctx context.Context, name string) (models.Prediction, error)
+}
+
+type JobStore interface {
+	ClaimEnrichmentJob(ctx context.Context, lease time.Duration) (models.EnrichmentJob, error)
+	CompleteEnrichment(ctx context.Context, job models.EnrichmentJob, prediction models.Prediction) error
+	RetryEnrichmentJob(ctx context.Context, job models.EnrichmentJob, delay time.Duration, reason string) error
+	FailEnrichment(ctx context.Context, job models.EnrichmentJob) error
+}
+
+// Pool runs enrichment jobs from the durable queue with a fixed number of
+// workers.
+type Pool struct {
+	log      *slog.Logger
+	enricher Enricher
+	store    JobStore
+	cfg      config.Worker
+}
+
+func New(log *slog.Logger, enricher Enricher, store JobStore, cfg config.Worker) (*Pool, error) {
+	const op = "worker.enrichment.New"
+
+	switch {
+	case cfg.PollInterval <= 0:
+		return nil, fmt.Errorf("%s: WORKER_POLL_INTERVAL must be positive", op)
+	case cfg.MaxAttempts < 1:
+		return nil, fmt.Errorf("%s: WORKER_MAX_ATTEMPTS must be at least 1", op)
+	case cfg.RetryBackoff <= 0:
+		return nil, fmt.Errorf("%s: WORKER_RETRY_BACKOFF must be positive", op)
+	case cfg.RetryBackoffMax < cfg.RetryBackoff:
+		return nil, fmt.Errorf("%s: WORKER_RETRY_BACKOFF_MAX must not be less than WORKER_RETRY_BACKOFF", op)
+	}
+
+	return &Pool{
+		log:      log.With(slog.String("component", "worker/enrichment")),
+		enricher: enricher,
+		store:    store,
+		cfg:      cfg,
+	}, nil
+}
+
+// Run blocks until ctx is done and every worker has finished its current job.
+func (p *Pool) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	for i := 0; i < p.cfg.Count; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			p.work(ctx, p.log.With(slog.Int("worker", i)))
+		}()
+	}
+
+	p.log.Info("enrichment workers started", slog.Int("count", p.cfg.Count))
+
+	wg.Wait()
+
+	p.log.Info("enrichment workers stopped")
+}
+
+func (p *Pool) work(ctx context.Context, log *slog.Logger) {
+	ticker := time.NewTicker(p.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Drain every due job before waiting for the next tick.
+		for ctx.Err() == nil && p.runOne(ctx, log) {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOne processes a single job and reports whether there was one.
+func (p *Pool) runOne(ctx context.Context, log *slog.Logger) bool {
+	job, err := p.store.ClaimEnrichmentJob(ctx, p.cfg.Lease)
+	if errors.Is(err, storage.ErrNoJobs) {
+		return false
+	}
+	if err != nil {
+		log.Error("failed to claim job", sLogger.Error(err))
+
+		return false
+	}
+
+	log = log.With(
+		slog.Int64("job_id", job.ID),
+		slog.Int64("people_id", job.PeopleID),
+		slog.Int("attempt", job.Attempts),
+	)
+
+	jobCtx, cancel := context.WithTimeout(ctx, p.cfg.JobTimeout)
+	prediction, err := p.enricher.Enrich(jobCtx, job.Name)
+	cancel()
+
+	// The outcome of a finished call is recorded even during shutdown.
+	storeCtx := context.WithoutCancel(ctx)
+
+	if err == nil {
+		if err = p.store.CompleteEnrichment(storeCtx, job, prediction); err != nil {
+			log.Error("failed to complete job", sLogger.Error(err))
+		} else {
+			log.Info("people enriched")
+		}
+
+		return true
+	}
+
+	// The job stays locked until its lease runs out and is picked up again
+	// by the next run.
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if job.Attempts >= p.cfg.MaxAttempts {
+		log.Error("enrichment failed, giving up", sLogger.Error(err))
+
+		if err = p.store.FailEnrichment(storeCtx, job); err != nil {
+			log.Error("failed to mark job as failed", sLogger.Error(err))
+		}
+
+		return true
+	}
+
+	delay := p.backoff(job.Attempts)
+
+	log.Warn("enrichment failed, retrying", sLogger.Error(err), slog.Duration("delay", delay))
+
+	if err = p.store.RetryEnrichmentJob(storeCtx, job, delay, err.Error()); err != nil {
+		log.Error("failed to reschedule job", sLogger.Error(err))
+	}
+
+	return true
+}
+
+// backoff returns the delay after the given failed attempt, doubling from
+// RetryBackoff and capped at RetryBackoffMax.
+func (p *Pool) backoff(attempt int) time.Duration {
+	shift := max(attempt-1, 0)
+
+	if shift >= 63 || p.cfg.RetryBackoff > p.cfg.RetryBackoffMax>>shift {
+		return p.cfg.RetryBackoffMax
+	}
+
+	return p.cfg.RetryBackoff << shift
+}
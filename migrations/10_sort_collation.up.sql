@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_people_info_name_id;
+DROP INDEX IF EXISTS idx_people_info_surname_id;
+CREATE INDEX IF NOT EXISTS idx_people_info_name_id ON people_info (name COLLATE "C", id);
+CREATE INDEX IF NOT EXISTS idx_people_info_surname_id ON people_info (surname COLLATE "C", id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS nationality_prediction;
+DROP TABLE IF EXISTS people_prediction;
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS people_prediction
+(
+    people_id INTEGER PRIMARY KEY,
+    age INTEGER NOT NULL,
+    age_count INTEGER NOT NULL,
+    gender_name TEXT NOT NULL,
+    gender_probability DOUBLE PRECISION NOT NULL,
+    gender_count INTEGER NOT NULL,
+    nationality_count INTEGER NOT NULL,
+    predicted_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    FOREIGN KEY (people_id) REFERENCES people_info(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS nationality_prediction
+(
+    people_id INTEGER NOT NULL,
+    nationality_name TEXT NOT NULL,
+    probability DOUBLE PRECISION NOT NULL,
+    PRIMARY KEY (people_id, nationality_name),
+    FOREIGN KEY (people_id) REFERENCES people_info(id) ON DELETE CASCADE
+);
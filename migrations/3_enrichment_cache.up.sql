@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS enrichment_cache
+(
+    kind TEXT NOT NULL,
+    name TEXT NOT NULL,
+    locale TEXT NOT NULL DEFAULT '',
+    payload JSONB NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (kind, name, locale)
+);
+CREATE INDEX IF NOT EXISTS idx_enrichment_cache_expires_at ON enrichment_cache (expires_at);
@@ -0,0 +1,13 @@
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM people_info WHERE age IS NULL OR gender_id IS NULL OR nationality_id IS NULL) THEN
+        RAISE EXCEPTION 'people_info has rows without age, gender or nationality, enrich or delete them before migrating down';
+    END IF;
+END
+$$;
+
+DROP TABLE IF EXISTS enrichment_job;
+ALTER TABLE people_info ALTER COLUMN age SET NOT NULL;
+ALTER TABLE people_info ALTER COLUMN gender_id SET NOT NULL;
+ALTER TABLE people_info ALTER COLUMN nationality_id SET NOT NULL;
+ALTER TABLE people_info DROP COLUMN IF EXISTS enrichment_status;
@@ -0,0 +1,20 @@
+ALTER TABLE people_info
+    ADD COLUMN IF NOT EXISTS enrichment_status TEXT NOT NULL DEFAULT 'complete'
+        CHECK (enrichment_status IN ('pending', 'complete', 'failed'));
+ALTER TABLE people_info ALTER COLUMN enrichment_status SET DEFAULT 'pending';
+ALTER TABLE people_info ALTER COLUMN age DROP NOT NULL;
+ALTER TABLE people_info ALTER COLUMN gender_id DROP NOT NULL;
+ALTER TABLE people_info ALTER COLUMN nationality_id DROP NOT NULL;
+
+CREATE TABLE IF NOT EXISTS enrichment_job
+(
+    id SERIAL PRIMARY KEY,
+    people_id INTEGER UNIQUE NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    run_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    locked_until TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    FOREIGN KEY (people_id) REFERENCES people_info(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_enrichment_job_run_at ON enrichment_job (run_at);
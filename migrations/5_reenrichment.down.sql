@@ -0,0 +1,3 @@
+ALTER TABLE enrichment_job DROP COLUMN IF EXISTS overwrite_manual;
+DROP TABLE IF EXISTS enrichment_change;
+DROP TABLE IF EXISTS attribute_provenance;
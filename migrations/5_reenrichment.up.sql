@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS attribute_provenance
+(
+    people_id INTEGER NOT NULL,
+    field TEXT NOT NULL CHECK (field IN ('age', 'gender', 'nationality')),
+    source TEXT NOT NULL CHECK (source IN ('predicted', 'manual')),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (people_id, field),
+    FOREIGN KEY (people_id) REFERENCES people_info(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS enrichment_change
+(
+    id SERIAL PRIMARY KEY,
+    people_id INTEGER NOT NULL,
+    field TEXT NOT NULL,
+    old_value TEXT,
+    new_value TEXT NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    FOREIGN KEY (people_id) REFERENCES people_info(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_enrichment_change_people_id ON enrichment_change (people_id);
+
+ALTER TABLE enrichment_job ADD COLUMN IF NOT EXISTS overwrite_manual BOOLEAN NOT NULL DEFAULT false;
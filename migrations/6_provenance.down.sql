@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_attribute_provenance_source_probability;
+ALTER TABLE attribute_provenance DROP COLUMN IF EXISTS probability;
+ALTER TABLE attribute_provenance DROP COLUMN IF EXISTS provider;
@@ -0,0 +1,3 @@
+ALTER TABLE attribute_provenance ADD COLUMN IF NOT EXISTS provider TEXT;
+ALTER TABLE attribute_provenance ADD COLUMN IF NOT EXISTS probability DOUBLE PRECISION;
+CREATE INDEX IF NOT EXISTS idx_attribute_provenance_source_probability ON attribute_provenance (source, probability);
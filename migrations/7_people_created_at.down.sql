@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_people_info_age;
+DROP INDEX IF EXISTS idx_people_info_created_at;
+ALTER TABLE people_info DROP COLUMN IF EXISTS created_at;
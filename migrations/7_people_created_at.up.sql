@@ -0,0 +1,3 @@
+ALTER TABLE people_info ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+CREATE INDEX IF NOT EXISTS idx_people_info_created_at ON people_info (created_at);
+CREATE INDEX IF NOT EXISTS idx_people_info_age ON people_info (age);
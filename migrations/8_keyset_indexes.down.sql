@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_people_info_created_at_id;
+DROP INDEX IF EXISTS idx_people_info_age_id;
+DROP INDEX IF EXISTS idx_people_info_surname_id;
+DROP INDEX IF EXISTS idx_people_info_name_id;
@@ -0,0 +1,4 @@
+CREATE INDEX IF NOT EXISTS idx_people_info_name_id ON people_info (name, id);
+CREATE INDEX IF NOT EXISTS idx_people_info_surname_id ON people_info (surname, id);
+CREATE INDEX IF NOT EXISTS idx_people_info_age_id ON people_info (COALESCE(age, 0), id);
+CREATE INDEX IF NOT EXISTS idx_people_info_created_at_id ON people_info (created_at, id);
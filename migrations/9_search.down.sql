@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_people_info_full_name_trgm;
+DROP INDEX IF EXISTS idx_people_info_search;
+ALTER TABLE people_info DROP COLUMN IF EXISTS search;
+DROP EXTENSION IF EXISTS pg_trgm;
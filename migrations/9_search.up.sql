@@ -0,0 +1,8 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE people_info ADD COLUMN IF NOT EXISTS search TSVECTOR
+    GENERATED ALWAYS AS (to_tsvector('simple', name || ' ' || surname || ' ' || COALESCE(patronym, ''))) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_people_info_search ON people_info USING GIN (search);
+CREATE INDEX IF NOT EXISTS idx_people_info_full_name_trgm ON people_info
+    USING GIN ((name || ' ' || surname || ' ' || COALESCE(patronym, '')) gin_trgm_ops);